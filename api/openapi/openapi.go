@@ -0,0 +1,15 @@
+// Package openapi contains the OpenAPI documents of the application.
+//
+// todo.yaml is a copy of the OpenAPI document of the todo API (see the openapi Makefile target),
+// todo_list.swagger.json is generated from the TodoListService (see the generate-gateway Makefile target).
+package openapi
+
+import "embed"
+
+//go:embed todo.yaml todo_list.swagger.json
+var files embed.FS
+
+// Files returns a filesystem with OpenAPI documents.
+func Files() embed.FS {
+	return files
+}
@@ -3,29 +3,56 @@ package main
 import (
 	"errors"
 	"os"
+	"reflect"
 	"strings"
 	"time"
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 
+	"github.com/sagikazarmark/modern-go-application/internal/platform/cors"
 	"github.com/sagikazarmark/modern-go-application/internal/platform/database"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/errorhandler"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/gosundheit"
 	"github.com/sagikazarmark/modern-go-application/internal/platform/log"
 	"github.com/sagikazarmark/modern-go-application/internal/platform/opencensus"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/ratelimit"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/redact"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/securityheaders"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/tlsconfig"
 )
 
 // configuration holds any kind of configuration that comes from the outside world and
 // is necessary for running the application.
 type configuration struct {
+	// Maximum time to wait for in-flight requests and event handlers during shutdown
+	// (0 closes them immediately, eg. in development)
+	ShutdownTimeout time.Duration
+
+	// Time to wait after failing the readiness probe before draining servers
+	PreStopDelay time.Duration
+
 	// Log configuration
 	Log log.Config
 
+	// Error handler configuration
+	ErrorHandler errorhandler.Config
+
+	// Redaction of sensitive information in logs and errors
+	Redact redact.Config
+
 	// Telemetry configuration
 	Telemetry struct {
 		// Telemetry HTTP server address
 		Addr string
+
+		// Telemetry HTTP server TLS configuration
+		TLS tlsconfig.Config
 	}
 
+	// Health check configuration
+	Healthcheck gosundheit.Config
+
 	// OpenCensus configuration
 	Opencensus struct {
 		Exporter struct {
@@ -45,16 +72,46 @@ type configuration struct {
 }
 
 // Process post-processes configuration after loading it.
-func (configuration) Process() error {
-	return nil
+//
+// Secrets referenced in configuration values (see resolveSecrets) are resolved.
+func (c *configuration) Process() error {
+	return resolveSecrets(reflect.ValueOf(c), "", true)
 }
 
 // Validate validates the configuration.
 func (c configuration) Validate() error {
+	if c.ShutdownTimeout < 0 {
+		return errors.New("shutdown timeout cannot be negative")
+	}
+
+	if c.PreStopDelay < 0 {
+		return errors.New("pre-stop delay cannot be negative")
+	}
+
+	if err := c.Log.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.ErrorHandler.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Redact.Validate(); err != nil {
+		return err
+	}
+
 	if c.Telemetry.Addr == "" {
 		return errors.New("telemetry http server address is required")
 	}
 
+	if err := c.Telemetry.TLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.Healthcheck.Validate(); err != nil {
+		return err
+	}
+
 	if err := c.App.Validate(); err != nil {
 		return err
 	}
@@ -68,34 +125,84 @@ func (c configuration) Validate() error {
 
 // appConfig represents the application related configuration.
 type appConfig struct {
+	// Addr is the address of a single port serving HTTP, gRPC and gRPC-Web (optional).
+	// When set, HttpAddr and GrpcAddr are ignored and HttpTLS configures TLS for every protocol.
+	Addr string
+
 	// HTTP server address
 	// nolint: golint, stylecheck
 	HttpAddr string
 
+	// HTTP server TLS configuration
+	// nolint: golint, stylecheck
+	HttpTLS tlsconfig.Config
+
 	// GRPC server address
 	GrpcAddr string
 
+	// GRPC server TLS configuration
+	GrpcTLS tlsconfig.Config
+
 	// Storage is the storage backend of the application
 	Storage string
+
+	// Cross-Origin Resource Sharing configuration of the HTTP server
+	CORS cors.Config
+
+	// Security headers of HTTP responses
+	SecurityHeaders securityheaders.Config
+
+	// Rate limiting of API requests (HTTP, gRPC and GraphQL)
+	RateLimit ratelimit.Config
 }
 
 // Validate validates the configuration.
 func (c appConfig) Validate() error {
-	if c.HttpAddr == "" {
-		return errors.New("http app server address is required")
+	if c.Addr != "" {
+		if c.GrpcTLS.Enabled {
+			return errors.New("grpc tls cannot be enabled when serving on a single port (configure http tls instead)")
+		}
+	} else {
+		if c.HttpAddr == "" {
+			return errors.New("http app server address is required")
+		}
+
+		if c.GrpcAddr == "" {
+			return errors.New("grpc app server address is required")
+		}
 	}
 
-	if c.GrpcAddr == "" {
-		return errors.New("grpc app server address is required")
+	if err := c.HttpTLS.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.GrpcTLS.Validate(); err != nil {
+		return err
 	}
 
 	if c.Storage != "inmemory" && c.Storage != "database" {
 		return errors.New("app storage must be inmemory or database")
 	}
 
+	if err := c.CORS.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.SecurityHeaders.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.RateLimit.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
+// envKeyReplacer maps configuration keys to environment variable names.
+// nolint: gochecknoglobals
+var envKeyReplacer = strings.NewReplacer(".", "_", "-", "_")
+
 // configure configures some defaults in the Viper instance.
 func configure(v *viper.Viper, f *pflag.FlagSet) {
 	// Viper settings
@@ -103,25 +210,43 @@ func configure(v *viper.Viper, f *pflag.FlagSet) {
 	v.AddConfigPath("$CONFIG_DIR/")
 
 	// Environment variable settings
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
+	v.SetEnvKeyReplacer(envKeyReplacer)
 	v.AllowEmptyEnv(true)
 	v.AutomaticEnv()
 
 	// Global configuration
 	v.SetDefault("shutdownTimeout", 15*time.Second)
+	v.SetDefault("preStopDelay", 0)
 	if _, ok := os.LookupEnv("NO_COLOR"); ok {
 		v.SetDefault("no_color", true)
 	}
 
 	// Log configuration
+	v.SetDefault("log.backend", "logrus")
 	v.SetDefault("log.format", "json")
 	v.SetDefault("log.level", "info")
 	v.RegisterAlias("log.noColor", "no_color")
 
+	// Error handler configuration
+	v.SetDefault("errorHandler.handlers", []string{"log"})
+	_ = v.BindEnv("errorHandler.sentry.dsn")
+	_ = v.BindEnv("errorHandler.sentry.environment")
+	v.SetDefault("errorHandler.sentry.timeout", 5*time.Second)
+	v.SetDefault("errorHandler.rateLimit.limit", 10)
+	v.SetDefault("errorHandler.rateLimit.burst", 20)
+	v.SetDefault("errorHandler.deduplication.window", time.Minute)
+
 	// Telemetry configuration
 	f.String("telemetry-addr", ":10000", "Telemetry HTTP server address")
-	_ = v.BindPFlag("telemetry.addr", f.Lookup("telemetry-addr"))
+	bindFlag(v, f, "telemetry.addr", "telemetry-addr")
 	v.SetDefault("telemetry.addr", ":10000")
+	configureTLSDefaults(v, "telemetry.tls")
+
+	// Health check configuration
+	v.SetDefault("healthcheck.period", 3*time.Second)
+	v.SetDefault("healthcheck.disk.enabled", false)
+	v.SetDefault("healthcheck.disk.path", ".")
+	v.SetDefault("healthcheck.disk.minFreePercent", 5)
 
 	// OpenCensus configuration
 	v.SetDefault("opencensus.exporter.enabled", false)
@@ -133,14 +258,46 @@ func configure(v *viper.Viper, f *pflag.FlagSet) {
 
 	// App configuration
 	f.String("http-addr", ":8000", "App HTTP server address")
-	_ = v.BindPFlag("app.httpAddr", f.Lookup("http-addr"))
+	bindFlag(v, f, "app.httpAddr", "http-addr")
 	v.SetDefault("app.httpAddr", ":8000")
 
 	f.String("grpc-addr", ":8001", "App GRPC server address")
-	_ = v.BindPFlag("app.grpcAddr", f.Lookup("grpc-addr"))
+	bindFlag(v, f, "app.grpcAddr", "grpc-addr")
 	v.SetDefault("app.grpcAddr", ":8001")
 
+	f.String("addr", "", "App server address serving HTTP and GRPC on a single port (overrides http-addr and grpc-addr)")
+	bindFlag(v, f, "app.addr", "addr")
+	v.SetDefault("app.addr", "")
+
+	configureTLSDefaults(v, "app.httpTLS")
+	configureTLSDefaults(v, "app.grpcTLS")
+
 	v.SetDefault("app.storage", "inmemory")
+	v.SetDefault("app.cors.allowedOrigins", []string{"*"})
+	v.SetDefault("app.cors.allowedMethods", []string{"GET", "POST", "PATCH", "DELETE"})
+	v.SetDefault("app.cors.allowedHeaders", []string{"content-type"})
+	v.SetDefault("app.cors.exposedHeaders", []string{
+		ratelimit.LimitHeader,
+		ratelimit.RemainingHeader,
+		ratelimit.ResetHeader,
+		ratelimit.RetryAfterHeader,
+	})
+	v.SetDefault("app.cors.allowCredentials", false)
+	v.SetDefault("app.cors.maxAge", 10*time.Minute)
+	v.SetDefault("app.securityHeaders.hsts.enabled", false)
+	v.SetDefault("app.securityHeaders.hsts.maxAge", 365*24*time.Hour)
+	v.SetDefault("app.securityHeaders.hsts.includeSubdomains", false)
+	v.SetDefault("app.securityHeaders.hsts.preload", false)
+	v.SetDefault("app.securityHeaders.contentTypeNosniff", true)
+	v.SetDefault("app.securityHeaders.referrerPolicy", "strict-origin-when-cross-origin")
+	v.SetDefault(
+		"app.securityHeaders.contentSecurityPolicy",
+		"default-src 'none'; style-src 'unsafe-inline'; img-src 'self'; frame-ancestors 'none'",
+	)
+	v.SetDefault("app.rateLimit.enabled", false)
+	v.SetDefault("app.rateLimit.key", ratelimit.KeyIP)
+	v.SetDefault("app.rateLimit.limit", 10)
+	v.SetDefault("app.rateLimit.burst", 20)
 
 	// Database configuration
 	_ = v.BindEnv("database.host")
@@ -152,3 +309,22 @@ func configure(v *viper.Viper, f *pflag.FlagSet) {
 		"collation": "utf8mb4_general_ci",
 	})
 }
+
+// configureTLSDefaults configures TLS defaults of a server in the Viper instance.
+func configureTLSDefaults(v *viper.Viper, key string) {
+	v.SetDefault(key+".enabled", false)
+	_ = v.BindEnv(key + ".certFile")
+	_ = v.BindEnv(key + ".keyFile")
+	_ = v.BindEnv(key + ".clientCAFile")
+	v.SetDefault(key+".minVersion", "1.2")
+}
+
+// configKeyAnnotation is the flag annotation holding the configuration key a flag is bound to.
+const configKeyAnnotation = "config_key"
+
+// bindFlag binds a flag to a configuration key.
+// The binding is recorded in the flag annotations, so that the source of configuration values can be reported.
+func bindFlag(v *viper.Viper, f *pflag.FlagSet, key string, name string) {
+	_ = v.BindPFlag(key, f.Lookup(name))
+	_ = f.SetAnnotation(name, configKeyAnnotation, []string{strings.ToLower(key)})
+}
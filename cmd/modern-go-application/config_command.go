@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/sagikazarmark/modern-go-application/internal/platform/redact"
+)
+
+const configCommandUsage = `Usage: %s config <command>
+
+Commands:
+  print     Print the effective configuration and the source of each value (secrets are redacted)
+  validate  Validate the configuration
+  schema    Print the JSON Schema of the configuration
+`
+
+// readConfiguration reads, unmarshals and processes the configuration.
+func readConfiguration(v *viper.Viper) (config configuration, configFileNotFound bool, err error) {
+	err = v.ReadInConfig()
+	_, configFileNotFound = err.(viper.ConfigFileNotFoundError)
+	if err != nil && !configFileNotFound {
+		return config, false, errors.Wrap(err, "failed to read configuration")
+	}
+
+	err = v.Unmarshal(&config)
+	if err != nil {
+		return config, configFileNotFound, errors.Wrap(err, "failed to unmarshal configuration")
+	}
+
+	err = config.Process()
+	if err != nil {
+		return config, configFileNotFound, errors.WithMessage(err, "failed to process configuration")
+	}
+
+	return config, configFileNotFound, nil
+}
+
+// runConfigCommand runs a configuration command and returns the exit code.
+func runConfigCommand(v *viper.Viper, f *pflag.FlagSet, args []string, stdout io.Writer, stderr io.Writer) int {
+	var command string
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	var err error
+
+	switch command {
+	case "print":
+		err = printConfiguration(v, f, stdout)
+
+	case "validate":
+		err = validateConfiguration(v, stdout)
+
+	case "schema":
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(configurationSchema())
+
+	default:
+		fmt.Fprintf(stderr, configCommandUsage, os.Args[0])
+
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintln(stderr, err.Error())
+
+		return 1
+	}
+
+	return 0
+}
+
+func validateConfiguration(v *viper.Viper, w io.Writer) error {
+	config, _, err := readConfiguration(v)
+	if err != nil {
+		return err
+	}
+
+	err = config.Validate()
+	if err != nil {
+		return errors.WithMessage(err, "invalid configuration")
+	}
+
+	fmt.Fprintln(w, "configuration is valid")
+
+	return nil
+}
+
+func printConfiguration(v *viper.Viper, f *pflag.FlagSet, w io.Writer) error {
+	config, _, err := readConfiguration(v)
+	if err != nil {
+		return err
+	}
+
+	redactor, err := redact.NewRedactor(config.Redact)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create redactor")
+	}
+
+	sources, err := configurationSources(v, f)
+	if err != nil {
+		return err
+	}
+
+	values := redactor.RedactFields(settings(v))
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+
+	for _, key := range keys {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", key, formatConfigurationValue(values[key]), sources[key])
+	}
+
+	return tw.Flush()
+}
+
+// settings returns the current value of every configuration key.
+func settings(v *viper.Viper) map[string]interface{} {
+	settings := make(map[string]interface{})
+
+	for _, key := range v.AllKeys() {
+		settings[key] = v.Get(key)
+	}
+
+	return settings
+}
+
+// configurationSources returns the source (flag, env, file or default) of every configuration value.
+func configurationSources(v *viper.Viper, f *pflag.FlagSet) (map[string]string, error) {
+	flagKeys := make(map[string]bool)
+
+	f.Visit(func(flag *pflag.Flag) {
+		for _, key := range flag.Annotations[configKeyAnnotation] {
+			flagKeys[key] = true
+		}
+	})
+
+	file := viper.New()
+
+	if configFile := v.ConfigFileUsed(); configFile != "" {
+		file.SetConfigFile(configFile)
+
+		err := file.ReadInConfig()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read configuration")
+		}
+	}
+
+	sources := make(map[string]string)
+
+	for _, key := range v.AllKeys() {
+		envName := strings.ToUpper(envKeyReplacer.Replace(key))
+
+		_, env := os.LookupEnv(envName)
+		if _, fileEnv := os.LookupEnv(envName + fileEnvSuffix); fileEnv {
+			env = true
+		}
+
+		switch {
+		case flagKeys[key]:
+			sources[key] = "flag"
+
+		case env:
+			sources[key] = "env"
+
+		case file.IsSet(key):
+			sources[key] = "file"
+
+		default:
+			sources[key] = "default"
+		}
+	}
+
+	return sources, nil
+}
+
+func formatConfigurationValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+
+	case time.Duration:
+		return v.String()
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(b)
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newConfigCommandTest(t *testing.T, args ...string) (*viper.Viper, *pflag.FlagSet) {
+	t.Helper()
+
+	v := viper.New()
+	f := pflag.NewFlagSet("test", pflag.ContinueOnError)
+
+	configure(v, f)
+
+	require.NoError(t, f.Parse(args))
+
+	const config = `
+[app]
+storage = "inmemory"
+
+[database]
+host = "localhost"
+user = "root"
+name = "app"
+`
+
+	configFile := filepath.Join(t.TempDir(), "config.toml")
+
+	require.NoError(t, ioutil.WriteFile(configFile, []byte(config), 0600))
+
+	v.SetConfigFile(configFile)
+
+	return v, f
+}
+
+func TestConfigCommand_Print(t *testing.T) {
+	t.Setenv("DATABASE_PASS", "hunter2")
+
+	v, f := newConfigCommandTest(t, "--http-addr", ":9000")
+
+	var stdout, stderr bytes.Buffer
+
+	code := runConfigCommand(v, f, []string{"print"}, &stdout, &stderr)
+	require.Equal(t, 0, code, stderr.String())
+
+	output := stdout.String()
+
+	assert.Regexp(t, `app\.httpaddr\s+":9000"\s+flag`, output)
+	assert.Regexp(t, `database\.pass\s+"\[REDACTED\]"\s+env`, output)
+	assert.Regexp(t, `database\.host\s+"localhost"\s+file`, output)
+	assert.Regexp(t, `healthcheck\.disk\.minfreepercent\s+5\s+default`, output)
+	assert.NotContains(t, output, "hunter2")
+}
+
+func TestConfigCommand_Validate(t *testing.T) {
+	v, f := newConfigCommandTest(t)
+
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, 0, runConfigCommand(v, f, []string{"validate"}, &stdout, &stderr))
+
+	v.Set("app.storage", "redis")
+
+	assert.Equal(t, 1, runConfigCommand(v, f, []string{"validate"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "app storage must be inmemory or database")
+}
+
+func TestConfigCommand_Schema(t *testing.T) {
+	v, f := newConfigCommandTest(t)
+
+	var stdout, stderr bytes.Buffer
+
+	require.Equal(t, 0, runConfigCommand(v, f, []string{"schema"}, &stdout, &stderr))
+
+	var schema struct {
+		Properties map[string]struct {
+			Type       interface{}                       `json:"type"`
+			Properties map[string]map[string]interface{} `json:"properties"`
+		} `json:"properties"`
+	}
+
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &schema))
+
+	assert.Equal(t, "object", schema.Properties["app"].Type)
+	assert.Equal(t, "string", schema.Properties["app"].Properties["httpAddr"]["type"])
+	assert.Equal(t, "boolean", schema.Properties["opencensus"].Properties["exporter"]["properties"].(map[string]interface{})["insecure"].(map[string]interface{})["type"])
+	assert.Contains(t, schema.Properties["shutdownTimeout"].Type, "string")
+}
+
+func TestLowerCamelCase(t *testing.T) {
+	tests := map[string]string{
+		"HttpAddr":        "httpAddr",
+		"CORS":            "cors",
+		"DSN":             "dsn",
+		"URLPath":         "urlPath",
+		"ShutdownTimeout": "shutdownTimeout",
+	}
+
+	for input, expected := range tests {
+		assert.Equal(t, expected, lowerCamelCase(input))
+	}
+}
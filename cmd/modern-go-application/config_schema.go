@@ -0,0 +1,137 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// durationPattern matches duration strings accepted by time.ParseDuration.
+const durationPattern = `^([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+$`
+
+// configurationSchema returns a JSON Schema describing the configuration.
+func configurationSchema() map[string]interface{} {
+	schema := typeSchema(reflect.TypeOf(configuration{}))
+
+	schema["$schema"] = "http://json-schema.org/draft-07/schema#"
+	schema["title"] = friendlyAppName + " configuration"
+
+	return schema
+}
+
+// nolint: gochecknoglobals
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == durationType {
+		return map[string]interface{}{
+			"type":    []string{"string", "integer"},
+			"pattern": durationPattern,
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{
+			"type":  "array",
+			"items": typeSchema(t.Elem()),
+		}
+
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": typeSchema(t.Elem()),
+		}
+
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+
+		addStructProperties(t, properties)
+
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": properties,
+		}
+	}
+
+	return map[string]interface{}{}
+}
+
+// addStructProperties adds the fields of a struct to the properties of a schema.
+func addStructProperties(t reflect.Type, properties map[string]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.PkgPath != "" {
+			continue
+		}
+
+		name, squash := configurationFieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		if squash {
+			addStructProperties(field.Type, properties)
+
+			continue
+		}
+
+		properties[name] = typeSchema(field.Type)
+	}
+}
+
+// configurationFieldName returns the configuration key of a struct field and whether its fields are squashed
+// into the parent struct (following the mapstructure conventions used when unmarshaling the configuration).
+func configurationFieldName(field reflect.StructField) (name string, squash bool) {
+	name = field.Name
+
+	if tag, ok := field.Tag.Lookup("mapstructure"); ok {
+		parts := strings.SplitN(tag, ",", 2)
+
+		if parts[0] == "-" {
+			return "-", false
+		}
+
+		if parts[0] != "" {
+			name = parts[0]
+		}
+
+		squash = len(parts) > 1 && parts[1] == "squash" && field.Type.Kind() == reflect.Struct
+	}
+
+	return lowerCamelCase(name), squash
+}
+
+// lowerCamelCase converts a Go field name to the key format used in configuration files (eg. HttpAddr -> httpAddr).
+func lowerCamelCase(s string) string {
+	runes := []rune(s)
+
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		// Keep the first letter of the next word in upper case (eg. URLPath -> urlPath)
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
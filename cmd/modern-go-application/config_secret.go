@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"reflect"
+	"strings"
+
+	"emperror.dev/errors"
+
+	"github.com/sagikazarmark/modern-go-application/internal/platform/secret"
+)
+
+// fileEnvSuffix marks environment variables holding the path of a file a configuration value is read from
+// (eg. DATABASE_PASS_FILE).
+const fileEnvSuffix = "_FILE"
+
+// resolveSecrets resolves secrets in string configuration values:
+//   - values can be read from a file set in a <KEY>_FILE environment variable (eg. DATABASE_PASS_FILE)
+//   - file://<path> references are replaced by the content of the file
+//   - env://<name> references are replaced by the value of the environment variable
+//
+// <KEY>_FILE environment variables are not supported for values in lists.
+func resolveSecrets(v reflect.Value, key string, fileEnv bool) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+
+		return resolveSecrets(v.Elem(), key, fileEnv)
+
+	case reflect.String:
+		value := v.String()
+
+		if fileEnv {
+			if path, ok := os.LookupEnv(strings.ToUpper(envKeyReplacer.Replace(key)) + fileEnvSuffix); ok {
+				value = secret.FilePrefix + path
+			}
+		}
+
+		if !secret.IsReference(value) {
+			return nil
+		}
+
+		resolved, err := secret.Resolve(value)
+		if err != nil {
+			return errors.WithDetails(err, "key", key)
+		}
+
+		v.SetString(resolved)
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := resolveSecrets(v.Index(i), key, false); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+
+		iter := v.MapRange()
+		for iter.Next() {
+			value := iter.Value().String()
+			if !secret.IsReference(value) {
+				continue
+			}
+
+			resolved, err := secret.Resolve(value)
+			if err != nil {
+				return errors.WithDetails(err, "key", key+"."+iter.Key().String())
+			}
+
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(resolved).Convert(v.Type().Elem()))
+		}
+
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			if field.PkgPath != "" {
+				continue
+			}
+
+			name, squash := configurationFieldName(field)
+			if name == "-" {
+				continue
+			}
+
+			fieldKey := key
+			if !squash {
+				fieldKey = joinKey(key, name)
+			}
+
+			if err := resolveSecrets(v.Field(i), fieldKey, fileEnv); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func joinKey(prefix string, name string) string {
+	if prefix == "" {
+		return name
+	}
+
+	return prefix + "." + name
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfiguration_Process(t *testing.T) {
+	dir := t.TempDir()
+
+	passwordFile := filepath.Join(dir, "db-password")
+	require.NoError(t, ioutil.WriteFile(passwordFile, []byte("db-secret\n"), 0400))
+
+	dsnFile := filepath.Join(dir, "sentry-dsn")
+	require.NoError(t, ioutil.WriteFile(dsnFile, []byte("https://public@sentry.example.com/1"), 0400))
+
+	t.Setenv("DATABASE_PASS_FILE", passwordFile)
+	t.Setenv("TEST_TLS_CA", "/etc/ssl/ca.pem")
+
+	var config configuration
+
+	config.Database.Pass = "plain"
+	config.ErrorHandler.Sentry.DSN = "file://" + dsnFile
+	config.Database.Params = map[string]string{"tls-ca": "env://TEST_TLS_CA"}
+
+	err := config.Process()
+	require.NoError(t, err)
+
+	assert.Equal(t, "db-secret", config.Database.Pass)
+	assert.Equal(t, "https://public@sentry.example.com/1", config.ErrorHandler.Sentry.DSN)
+	assert.Equal(t, "/etc/ssl/ca.pem", config.Database.Params["tls-ca"])
+}
+
+func TestConfiguration_Process_Error(t *testing.T) {
+	var config configuration
+
+	config.Database.Pass = "env://TEST_MISSING_DATABASE_PASS"
+
+	err := config.Process()
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "referenced environment variable is not set")
+}
@@ -6,7 +6,10 @@ import (
 
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/sagikazarmark/modern-go-application/internal/platform/tlsconfig"
 )
 
 func TestConfigure(t *testing.T) {
@@ -30,4 +33,25 @@ func TestConfigure(t *testing.T) {
 
 	err = config.Validate()
 	require.NoError(t, err)
+
+	// The development config (see the config.toml Makefile target) closes connections immediately
+	config.ShutdownTimeout = 0
+	require.NoError(t, config.Validate())
+}
+
+func TestAppConfig_Validate_SinglePort(t *testing.T) {
+	config := appConfig{
+		Addr:    ":8000",
+		Storage: "inmemory",
+	}
+
+	require.NoError(t, config.Validate(), "http and grpc addresses should not be required")
+
+	config.GrpcTLS = tlsconfig.Config{
+		Enabled:  true,
+		CertFile: "server.crt",
+		KeyFile:  "server.key",
+	}
+
+	assert.EqualError(t, config.Validate(), "grpc tls cannot be enabled when serving on a single port (configure http tls instead)")
 }
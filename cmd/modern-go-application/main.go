@@ -2,12 +2,14 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"database/sql"
 	"fmt"
 	"net/http"
 	_ "net/http/pprof" // register pprof HTTP handlers #nosec
 	"os"
 	"os/signal"
+	"reflect"
 	"syscall"
 	"time"
 
@@ -17,12 +19,10 @@ import (
 	"emperror.dev/emperror"
 	"emperror.dev/errors"
 	"emperror.dev/errors/match"
-	logurhandler "emperror.dev/handler/logur"
 	health "github.com/AppsFlyer/go-sundheit"
 	"github.com/AppsFlyer/go-sundheit/checks"
-	healthhttp "github.com/AppsFlyer/go-sundheit/http"
 	"github.com/cloudflare/tableflip"
-	"github.com/gorilla/handlers"
+	"github.com/fsnotify/fsnotify"
 	"github.com/gorilla/mux"
 	"github.com/oklog/run"
 	"github.com/sagikazarmark/appkit/buildinfo"
@@ -37,15 +37,24 @@ import (
 	"go.opencensus.io/trace"
 	"go.opencensus.io/zpages"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"logur.dev/logur"
 
 	"github.com/sagikazarmark/modern-go-application/internal/app/mga"
 	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/tododriver"
 	"github.com/sagikazarmark/modern-go-application/internal/common/commonadapter"
 	"github.com/sagikazarmark/modern-go-application/internal/platform/appkit"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/cors"
 	"github.com/sagikazarmark/modern-go-application/internal/platform/database"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/errorhandler"
 	"github.com/sagikazarmark/modern-go-application/internal/platform/gosundheit"
 	"github.com/sagikazarmark/modern-go-application/internal/platform/log"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/multiplex"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/opencensus"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/ratelimit"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/redact"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/securityheaders"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/shutdown"
 	"github.com/sagikazarmark/modern-go-application/internal/platform/watermill"
 )
 
@@ -88,38 +97,74 @@ func main() {
 		v.SetConfigFile(c)
 	}
 
-	err := v.ReadInConfig()
-	_, configFileNotFound := err.(viper.ConfigFileNotFoundError)
-	if !configFileNotFound {
-		emperror.Panic(errors.Wrap(err, "failed to read configuration"))
+	// Configuration commands
+	if f.Arg(0) == "config" {
+		os.Exit(runConfigCommand(v, f, f.Args()[1:], os.Stdout, os.Stderr))
 	}
 
-	var config configuration
-	err = v.Unmarshal(&config)
-	emperror.Panic(errors.Wrap(err, "failed to unmarshal configuration"))
-
-	err = config.Process()
-	emperror.Panic(errors.WithMessage(err, "failed to process configuration"))
+	config, configFileNotFound, err := readConfiguration(v)
+	emperror.Panic(err)
 
 	// Create logger (first thing after configuration loading)
-	logger := log.NewLogger(config.Log)
-
-	// Override the global standard library logger to make sure everything uses our logger
-	log.SetStandardLogger(logger)
+	logLevels := log.NewLevelController(config.Log)
+	rootLogger, err := log.NewLogger(config.Log, logLevels)
+	emperror.Panic(errors.WithMessage(err, "failed to create logger"))
+	defer rootLogger.Close()
 
 	if configFileNotFound {
-		logger.Warn("configuration file not found")
+		rootLogger.Warn("configuration file not found")
 	}
 
 	err = config.Validate()
 	if err != nil {
-		logger.Error(err.Error())
+		rootLogger.Error(err.Error())
 
 		os.Exit(3)
 	}
 
+	redactor, err := redact.NewRedactor(config.Redact)
+	emperror.Panic(errors.WithMessage(err, "failed to create redactor"))
+
+	// Redact sensitive information from every log event (including those of libraries)
+	var logger logur.LoggerFacade = commonadapter.WithRedactor(rootLogger, redactor)
+
+	// Override the global standard library logger to make sure everything uses our logger
+	log.SetStandardLogger(logger)
+
+	// Apply configuration changes at runtime
+	configReloader := newConfigReloader(v, config, logger)
+	configReloader.OnReload(func(oldConfig configuration, newConfig configuration) error {
+		if newConfig.Log.Level != oldConfig.Log.Level {
+			level, _ := logur.ParseLevel(newConfig.Log.Level)
+
+			logLevels.SetLevel(level)
+		}
+
+		if newConfig.Log.Format != oldConfig.Log.Format || newConfig.Log.NoColor != oldConfig.Log.NoColor {
+			return rootLogger.SetFormat(newConfig.Log.Format, newConfig.Log.NoColor)
+		}
+
+		return nil
+	})
+
+	errorReporter, err := errorhandler.New(config.ErrorHandler, logger, appkit.ContextExtractor)
+	emperror.Panic(errors.WithMessage(err, "failed to create error handler"))
+	defer errorReporter.Close()
+
+	configReloader.OnReload(func(oldConfig configuration, newConfig configuration) error {
+		errorReporter.SetRateLimit(newConfig.ErrorHandler.RateLimit)
+
+		if newConfig.ErrorHandler.Sentry.DSN != oldConfig.ErrorHandler.Sentry.DSN {
+			err := errorReporter.SetSentryDSN(newConfig.ErrorHandler.Sentry.DSN)
+
+			return errors.WithMessage(err, "failed to change sentry dsn")
+		}
+
+		return nil
+	})
+
 	// Configure error handler
-	errorHandler := logurhandler.New(logger)
+	errorHandler := redact.ErrorHandler(errorReporter, redactor)
 	defer emperror.HandleRecover(errorHandler)
 
 	buildInfo := buildinfo.New(version, commitHash, buildDate)
@@ -128,6 +173,7 @@ func main() {
 
 	telemetryRouter := http.NewServeMux()
 	telemetryRouter.Handle("/buildinfo", buildinfo.HTTPHandler(buildInfo))
+	telemetryRouter.Handle("/loglevel", log.LevelHTTPHandler(logLevels, logger))
 
 	// Register pprof endpoints
 	telemetryRouter.Handle("/debug/pprof/", http.DefaultServeMux)
@@ -135,21 +181,53 @@ func main() {
 	// Configure health checker
 	healthChecker := health.New()
 	healthChecker.WithCheckListener(gosundheit.NewLogger(logur.WithField(logger, "component", "healthcheck")))
+	healthChecks := gosundheit.NewRegistry(healthChecker)
 	{
-		handler := healthhttp.HandleHealthJSON(healthChecker)
-		telemetryRouter.Handle("/healthz", handler)
+		telemetryRouter.Handle("/healthz", healthChecks.HTTPHandler())
 
 		// Kubernetes style health checks
-		telemetryRouter.HandleFunc("/healthz/live", func(w http.ResponseWriter, _ *http.Request) {
-			_, _ = w.Write([]byte("ok"))
-		})
-		telemetryRouter.Handle("/healthz/ready", handler)
+		telemetryRouter.Handle("/healthz/live", healthChecks.ProbeHTTPHandler(gosundheit.Liveness))
+		telemetryRouter.Handle("/healthz/ready", healthChecks.ProbeHTTPHandler(gosundheit.Readiness))
+		telemetryRouter.Handle("/healthz/startup", healthChecks.ProbeHTTPHandler(gosundheit.Startup))
+	}
+
+	configReloader.OnReload(func(oldConfig configuration, newConfig configuration) error {
+		if newConfig.Healthcheck.Period == oldConfig.Healthcheck.Period {
+			return nil
+		}
+
+		// Checks are registered again in the background, so that reloading does not wait for running checks
+		errc := healthChecks.SetExecutionPeriod(newConfig.Healthcheck.Period)
+
+		go func() {
+			if err := <-errc; err != nil {
+				errorHandler.Handle(errors.WithMessage(err, "failed to change health check period"))
+			}
+		}()
+
+		return nil
+	})
+
+	if config.Healthcheck.Disk.Enabled {
+		err := healthChecks.RegisterCheck(&health.Config{
+			Check:           gosundheit.NewDiskSpaceCheck(config.Healthcheck.Disk),
+			ExecutionPeriod: config.Healthcheck.Period,
+		}, gosundheit.Readiness)
+		emperror.Panic(err)
 	}
 
 	zpages.Handle(telemetryRouter, "/debug")
 
 	trace.ApplyConfig(config.Opencensus.Trace.Config())
 
+	configReloader.OnReload(func(oldConfig configuration, newConfig configuration) error {
+		if !reflect.DeepEqual(newConfig.Opencensus.Trace, oldConfig.Opencensus.Trace) {
+			trace.ApplyConfig(newConfig.Opencensus.Trace.Config())
+		}
+
+		return nil
+	})
+
 	// Configure OpenCensus exporter
 	if config.Opencensus.Exporter.Enabled {
 		exporter, err := ocagent.NewExporter(append(
@@ -160,6 +238,13 @@ func main() {
 
 		trace.RegisterExporter(exporter)
 		view.RegisterExporter(exporter)
+
+		// The exporter buffers data while the agent is unreachable, so it does not affect any probes
+		err = healthChecks.RegisterCheck(&health.Config{
+			Check:           opencensus.NewExporterCheck(config.Opencensus.Exporter.ExporterConfig, time.Second),
+			ExecutionPeriod: config.Healthcheck.Period,
+		}, 0)
+		emperror.Panic(err)
 	}
 
 	// Configure Prometheus exporter
@@ -189,8 +274,23 @@ func main() {
 		}
 	}()
 
+	// Toggle debug logging on SIGUSR1
+	go func() {
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGUSR1)
+		for range ch {
+			level := logLevels.ToggleDebug()
+
+			logger.Info("log level changed", map[string]interface{}{"level": level.String()})
+		}
+	}()
+
 	var group run.Group
 
+	// Coordinate graceful shutdown of the application components
+	shutdownCoordinator := shutdown.NewCoordinator(config.PreStopDelay, config.ShutdownTimeout, logger)
+	shutdownCoordinator.OnPreStop(healthChecks.SetShuttingDown)
+
 	// Set up telemetry server
 	{
 		const name = "telemetry"
@@ -201,15 +301,30 @@ func main() {
 		ln, err := upg.Fds.Listen("tcp", config.Telemetry.Addr)
 		emperror.Panic(err)
 
+		tlsConfig, err := configureTLS(&group, config.Telemetry.TLS, logger)
+		emperror.Panic(errors.WithMessage(err, "failed to configure tls"))
+
+		if tlsConfig != nil {
+			ln = tls.NewListener(ln, tlsConfig)
+		}
+
 		server := &http.Server{
 			Handler:  telemetryRouter,
 			ErrorLog: log.NewErrorStandardLogger(logger),
 		}
 		defer server.Close()
 
+		// The telemetry server keeps serving health checks until every other component is shut down
 		group.Add(
 			func() error { return server.Serve(ln) },
-			func(err error) { _ = server.Shutdown(context.Background()) },
+			func(err error) {
+				shutdownCoordinator.Shutdown()
+
+				ctx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+				defer cancel()
+
+				_ = server.Shutdown(ctx)
+			},
 		)
 	}
 
@@ -226,14 +341,26 @@ func main() {
 	db := sql.OpenDB(dbConnector)
 	defer db.Close()
 
+	// Open connections keep using the old password, new ones use the rotated one
+	configReloader.OnReload(func(oldConfig configuration, newConfig configuration) error {
+		if newConfig.Database.Pass == oldConfig.Database.Pass {
+			return nil
+		}
+
+		return errors.WithMessage(dbConnector.SetPassword(newConfig.Database.Pass), "failed to change database password")
+	})
+
 	// Record DB stats every 5 seconds until we exit
 	defer ocsql.RecordStats(db, 5*time.Second)()
 
 	// Register database health check
-	_ = healthChecker.RegisterCheck(&health.Config{
-		Check:           checks.Must(checks.NewPingCheck("db.check", db, time.Millisecond*100)),
-		ExecutionPeriod: 3 * time.Second,
-	})
+	if config.App.Storage == "database" {
+		err := healthChecks.RegisterCheck(&health.Config{
+			Check:           checks.Must(checks.NewPingCheck("db.check", db, time.Millisecond*100)),
+			ExecutionPeriod: config.Healthcheck.Period,
+		}, gosundheit.Readiness|gosundheit.Startup)
+		emperror.Panic(err)
+	}
 
 	publisher, subscriber := watermill.NewPubSub(logger)
 	defer publisher.Close()
@@ -242,6 +369,12 @@ func main() {
 	publisher = watermill.PublisherCorrelationID(publisher)
 	subscriber = watermill.SubscriberCorrelationID(subscriber)
 
+	err = healthChecks.RegisterCheck(&health.Config{
+		Check:           watermill.NewPubSubCheck(publisher, subscriber, time.Second),
+		ExecutionPeriod: config.Healthcheck.Period,
+	}, gosundheit.Readiness)
+	emperror.Panic(err)
+
 	// Register stat views
 	err = view.Register(
 		// Health checks
@@ -263,6 +396,12 @@ func main() {
 		ocgrpc.ServerLatencyView,
 		ocgrpc.ServerCompletedRPCsView,
 
+		// Configuration
+		configReloadCountView,
+
+		// Rate limiting
+		ratelimit.ThrottledRequestCountView,
+
 		// Todo
 		tododriver.CreatedTodoItemCountView,
 		tododriver.CompleteTodoItemCountView,
@@ -277,16 +416,37 @@ func main() {
 		httpRouter := mux.NewRouter()
 		httpRouter.Use(ocmux.Middleware())
 
-		cors := handlers.CORS(
-			handlers.AllowedOrigins([]string{"*"}),
-			handlers.AllowedMethods([]string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete}),
-			handlers.AllowedHeaders([]string{"content-type"}),
-		)
+		corsHandler := cors.NewHandler(config.App.CORS, httpRouter)
+
+		configReloader.OnReload(func(_ configuration, newConfig configuration) error {
+			corsHandler.SetConfig(newConfig.App.CORS)
+
+			return nil
+		})
+
+		// Security headers are set on every response (including errors returned by the router and CORS preflight)
+		securityHeaders := securityheaders.NewHandler(config.App.SecurityHeaders)
+
+		configReloader.OnReload(func(_ configuration, newConfig configuration) error {
+			securityHeaders.SetConfig(newConfig.App.SecurityHeaders)
+
+			return nil
+		})
+
+		rateLimiter := ratelimit.NewLimiter(config.App.RateLimit)
+
+		configReloader.OnReload(func(oldConfig configuration, newConfig configuration) error {
+			if !reflect.DeepEqual(oldConfig.App.RateLimit, newConfig.App.RateLimit) {
+				rateLimiter.SetConfig(newConfig.App.RateLimit)
+			}
+
+			return nil
+		})
 
 		httpServer := &http.Server{
 			Handler: &ochttp.Handler{
 				// Handler: httpRouter,
-				Handler: cors(httpRouter),
+				Handler: securityHeaders.Middleware(corsHandler),
 				StartOptions: trace.StartOptions{
 					Sampler:  trace.AlwaysSample(),
 					SpanKind: trace.SpanKindServer,
@@ -297,52 +457,165 @@ func main() {
 		}
 		defer httpServer.Close()
 
-		grpcServer := grpc.NewServer(grpc.StatsHandler(&ocgrpc.ServerHandler{
-			StartOptions: trace.StartOptions{
-				Sampler:  trace.AlwaysSample(),
-				SpanKind: trace.SpanKindServer,
-			},
-			IsPublicEndpoint: true,
-		}))
+		grpcServerOptions := []grpc.ServerOption{
+			grpc.StatsHandler(&ocgrpc.ServerHandler{
+				StartOptions: trace.StartOptions{
+					Sampler:  trace.AlwaysSample(),
+					SpanKind: trace.SpanKindServer,
+				},
+				IsPublicEndpoint: true,
+			}),
+		}
+
+		grpcTLSConfig, err := configureTLS(&group, config.App.GrpcTLS, logur.WithField(logger, "protocol", "grpc"))
+		emperror.Panic(errors.WithMessage(err, "failed to configure tls"))
+
+		if grpcTLSConfig != nil {
+			grpcServerOptions = append(grpcServerOptions, grpc.Creds(credentials.NewTLS(grpcTLSConfig)))
+		}
+
+		grpcServer := grpc.NewServer(grpcServerOptions...)
 		defer grpcServer.Stop()
 
 		// In larger apps, this should be split up into smaller functions
 		{
 			logger := commonadapter.NewContextAwareLogger(logger, appkit.ContextExtractor)
 			errorHandler := emperror.WithFilter(
-				emperror.WithContextExtractor(errorHandler, appkit.ContextExtractor),
+				errorHandler,
 				appkiterrors.IsServiceError, // filter out service errors
 			)
 
-			mga.InitializeApp(httpRouter, grpcServer, publisher, config.App.Storage, db, logger, errorHandler)
+			todoStore, err := mga.NewTodoStore(config.App.Storage, db)
+			emperror.Panic(err)
+
+			mga.InitializeApp(
+				httpRouter,
+				securityHeaders.ContentSecurityPolicy,
+				grpcServer,
+				publisher,
+				todoStore,
+				rateLimiter,
+				logger,
+				errorHandler,
+			)
 
 			h, err := watermill.NewRouter(logger)
 			emperror.Panic(err)
 
+			var inFlight watermill.InFlight
+			h.AddMiddleware(inFlight.Middleware)
+
 			err = mga.RegisterEventHandlers(h, subscriber, logger)
 			emperror.Panic(err)
 
-			group.Add(func() error { return h.Run(context.Background()) }, func(e error) { _ = h.Close() })
+			routerCheck := watermill.NewRouterCheck(h)
+
+			err = healthChecks.RegisterCheck(&health.Config{
+				Check:           routerCheck,
+				ExecutionPeriod: config.Healthcheck.Period,
+			}, gosundheit.Readiness|gosundheit.Startup)
+			emperror.Panic(err)
+
+			// Restart the application when the router stops unexpectedly
+			err = healthChecks.RegisterCheck(&health.Config{
+				Check:           routerCheck.LivenessCheck(),
+				ExecutionPeriod: config.Healthcheck.Period,
+			}, gosundheit.Liveness)
+			emperror.Panic(err)
+
+			routerCtx, cancelRouter := context.WithCancel(context.Background())
+
+			shutdownCoordinator.Register(shutdown.Component{
+				Name:  "watermill.router",
+				Drain: func(ctx context.Context) error {
+					if err := watermill.CloseRouter(ctx, h); err != nil {
+						return err
+					}
+
+					return inFlight.Wait(ctx)
+				},
+				Close: func() error {
+					logger.Warn("canceling message router", map[string]interface{}{
+						"in_flight_handlers": inFlight.Handlers(),
+					})
+
+					// Canceling the router context closes subscriptions, so no more messages are handled
+					cancelRouter()
+
+					return nil
+				},
+				InFlight: inFlight.Count,
+			})
+
+			group.Add(func() error { return routerCheck.Run(routerCtx) }, shutdownCoordinator.Interrupt)
 		}
 
-		logger.Info("listening on address", map[string]interface{}{"address": config.App.HttpAddr})
+		httpTLSConfig, err := configureTLS(&group, config.App.HttpTLS, logur.WithField(logger, "protocol", "http"))
+		emperror.Panic(errors.WithMessage(err, "failed to configure tls"))
 
-		httpLn, err := upg.Fds.Listen("tcp", config.App.HttpAddr)
-		emperror.Panic(err)
+		shutdownCoordinator.Register(shutdown.HTTPServer(name+".http", httpServer))
+		shutdownCoordinator.Register(shutdown.GRPCServer(name+".grpc", grpcServer))
 
-		logger.Info("listening on address", map[string]interface{}{"address": config.App.GrpcAddr})
+		// Serve HTTP, gRPC and gRPC-Web on a single port
+		if config.App.Addr != "" {
+			logger.Info("listening on address", map[string]interface{}{"address": config.App.Addr})
 
-		grpcLn, err := upg.Fds.Listen("tcp", config.App.GrpcAddr)
-		emperror.Panic(err)
+			ln, err := upg.Fds.Listen("tcp", config.App.Addr)
+			emperror.Panic(err)
 
-		group.Add(
-			func() error { return httpServer.Serve(httpLn) },
-			func(err error) { _ = httpServer.Shutdown(context.Background()) },
-		)
-		group.Add(
-			func() error { return grpcServer.Serve(grpcLn) },
-			func(err error) { grpcServer.GracefulStop() },
-		)
+			if httpTLSConfig != nil {
+				ln = tls.NewListener(ln, httpTLSConfig)
+			}
+
+			muxLn := multiplex.NewListener(ln)
+
+			httpServer.Handler = multiplex.Handler(grpcServer, httpServer.Handler, corsHandler.AllowsOrigin)
+
+			// The shared listener is closed once both servers stopped serving
+			shutdownCoordinator.Register(shutdown.Component{
+				Name:  name + ".listener",
+				Drain: muxLn.Drain,
+				Close: muxLn.Close,
+			})
+
+			group.Add(muxLn.Serve, shutdownCoordinator.Interrupt)
+			group.Add(func() error { return httpServer.Serve(muxLn.HTTP()) }, shutdownCoordinator.Interrupt)
+			group.Add(func() error { return grpcServer.Serve(muxLn.GRPC()) }, shutdownCoordinator.Interrupt)
+		} else {
+			logger.Info("listening on address", map[string]interface{}{"address": config.App.HttpAddr})
+
+			httpLn, err := upg.Fds.Listen("tcp", config.App.HttpAddr)
+			emperror.Panic(err)
+
+			if httpTLSConfig != nil {
+				httpLn = tls.NewListener(httpLn, httpTLSConfig)
+			}
+
+			logger.Info("listening on address", map[string]interface{}{"address": config.App.GrpcAddr})
+
+			grpcLn, err := upg.Fds.Listen("tcp", config.App.GrpcAddr)
+			emperror.Panic(err)
+
+			group.Add(func() error { return httpServer.Serve(httpLn) }, shutdownCoordinator.Interrupt)
+			group.Add(func() error { return grpcServer.Serve(grpcLn) }, shutdownCoordinator.Interrupt)
+		}
+	}
+
+	// Reload configuration when the configuration file changes
+	if v.ConfigFileUsed() != "" {
+		v.OnConfigChange(func(e fsnotify.Event) {
+			logger.Info("reloading configuration", map[string]interface{}{"file": e.Name})
+
+			err := configReloader.Reload()
+			if err != nil {
+				errorHandler.Handle(errors.WithMessage(err, "failed to reload configuration"))
+
+				return
+			}
+
+			logger.Info("configuration reloaded")
+		})
+		v.WatchConfig()
 	}
 
 	// Setup signal handler
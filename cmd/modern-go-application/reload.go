@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/spf13/viper"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"logur.dev/logur"
+)
+
+// withReloadableSettings returns a copy of the configuration with reloadable settings taken from another one.
+//
+// Changes to any other setting are reported as ignored until the application is restarted.
+func (c configuration) withReloadableSettings(other configuration) configuration {
+	c.Log.Level = other.Log.Level
+	c.Log.Format = other.Log.Format
+	c.Log.NoColor = other.Log.NoColor
+	c.Opencensus.Trace = other.Opencensus.Trace
+	c.App.CORS = other.App.CORS
+	c.App.SecurityHeaders = other.App.SecurityHeaders
+	c.App.RateLimit = other.App.RateLimit
+	c.ErrorHandler.RateLimit = other.ErrorHandler.RateLimit
+	c.Healthcheck.Period = other.Healthcheck.Period
+
+	// Secrets that can be rotated without restart
+	c.Database.Pass = other.Database.Pass
+	c.ErrorHandler.Sentry.DSN = other.ErrorHandler.Sentry.DSN
+
+	return c
+}
+
+// nolint: gochecknoglobals
+var (
+	reloadResult, _ = tag.NewKey("result")
+
+	configReloadCount = stats.Int64("config_reload_count", "Number of configuration reloads", stats.UnitDimensionless)
+
+	configReloadCountView = &view.View{
+		Name:        "config_reload_count",
+		Description: "Count of configuration reloads",
+		Measure:     configReloadCount,
+		TagKeys:     []tag.Key{reloadResult},
+		Aggregation: view.Count(),
+	}
+)
+
+// configReloader applies configuration changes at runtime.
+//
+// Only reloadable settings are applied, changes to any other settings are logged and ignored.
+type configReloader struct {
+	v      *viper.Viper
+	logger logur.Logger
+
+	config   configuration
+	appliers []func(old configuration, new configuration) error
+
+	mu sync.Mutex
+}
+
+func newConfigReloader(v *viper.Viper, config configuration, logger logur.Logger) *configReloader {
+	return &configReloader{
+		v:      v,
+		logger: logger,
+		config: config,
+	}
+}
+
+// changedKeys returns the configuration keys of the values that differ between two configurations.
+func changedKeys(a reflect.Value, b reflect.Value, key string) []string {
+	if a.Kind() == reflect.Struct {
+		var keys []string
+
+		t := a.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+
+			if field.PkgPath != "" {
+				continue
+			}
+
+			name, squash := configurationFieldName(field)
+			if name == "-" {
+				continue
+			}
+
+			fieldKey := key
+			if !squash {
+				fieldKey = joinKey(key, name)
+			}
+
+			keys = append(keys, changedKeys(a.Field(i), b.Field(i), fieldKey)...)
+		}
+
+		return keys
+	}
+
+	if reflect.DeepEqual(a.Interface(), b.Interface()) {
+		return nil
+	}
+
+	return []string{key}
+}
+
+// OnReload registers a function applying changed configuration.
+func (r *configReloader) OnReload(fn func(old configuration, new configuration) error) {
+	r.appliers = append(r.appliers, fn)
+}
+
+// Reload applies the current configuration of the Viper instance.
+func (r *configReloader) Reload() error {
+	err := r.reload()
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	_ = stats.RecordWithTags(
+		context.Background(),
+		[]tag.Mutator{tag.Upsert(reloadResult, result)},
+		configReloadCount.M(1),
+	)
+
+	return err
+}
+
+func (r *configReloader) reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var config configuration
+
+	err := r.v.Unmarshal(&config)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal configuration")
+	}
+
+	err = config.Process()
+	if err != nil {
+		return errors.WithMessage(err, "failed to process configuration")
+	}
+
+	newConfig := r.config.withReloadableSettings(config)
+
+	// Non-reloadable settings are kept from the configuration the application started with,
+	// so changes (including rotated secrets) are reported until the application is restarted.
+	for _, key := range changedKeys(reflect.ValueOf(newConfig), reflect.ValueOf(config), "") {
+		r.logger.Warn("configuration key cannot be changed without restart, ignoring change", map[string]interface{}{
+			"key": key,
+		})
+	}
+
+	err = newConfig.Validate()
+	if err != nil {
+		return errors.WithMessage(err, "invalid configuration")
+	}
+
+	var errs []error
+
+	for _, apply := range r.appliers {
+		errs = append(errs, apply(r.config, newConfig))
+	}
+
+	r.config = newConfig
+
+	return errors.Combine(errs...)
+}
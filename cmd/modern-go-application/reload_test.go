@@ -0,0 +1,189 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"logur.dev/logur"
+)
+
+func TestConfigReloader(t *testing.T) {
+	v := viper.New()
+	p := pflag.NewFlagSet("test", pflag.ContinueOnError)
+
+	configure(v, p)
+
+	file, err := os.Open("../../config.toml.dist")
+	require.NoError(t, err)
+	defer file.Close()
+
+	v.SetConfigType("toml")
+
+	err = v.ReadConfig(file)
+	require.NoError(t, err)
+
+	var config configuration
+
+	err = v.Unmarshal(&config)
+	require.NoError(t, err)
+
+	logger := &logur.TestLoggerFacade{}
+
+	reloader := newConfigReloader(v, config, logger)
+
+	var applied configuration
+
+	reloader.OnReload(func(_ configuration, newConfig configuration) error {
+		applied = newConfig
+
+		return nil
+	})
+
+	v.Set("log.level", "debug")
+	v.Set("app.httpAddr", ":9000")
+
+	err = reloader.Reload()
+	require.NoError(t, err)
+
+	assert.Equal(t, "debug", applied.Log.Level)
+	assert.Equal(t, config.App.HttpAddr, applied.App.HttpAddr, "non-reloadable keys should not change")
+
+	event := logger.LastEvent()
+	require.NotNil(t, event)
+	assert.Equal(t, logur.Warn, event.Level)
+	assert.Equal(t, "app.httpAddr", event.Fields["key"])
+
+	v.Set("log.level", "verbose")
+
+	err = reloader.Reload()
+	require.Error(t, err)
+
+	assert.Equal(t, "debug", applied.Log.Level, "invalid configuration should not be applied")
+}
+
+func TestConfigReloader_RotatedSecret(t *testing.T) {
+	v := viper.New()
+	p := pflag.NewFlagSet("test", pflag.ContinueOnError)
+
+	configure(v, p)
+
+	file, err := os.Open("../../config.toml.dist")
+	require.NoError(t, err)
+	defer file.Close()
+
+	v.SetConfigType("toml")
+
+	err = v.ReadConfig(file)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "password")
+
+	err = ioutil.WriteFile(path, []byte("secret"), 0600)
+	require.NoError(t, err)
+
+	v.Set("database.pass", "file://"+path)
+
+	var config configuration
+
+	err = v.Unmarshal(&config)
+	require.NoError(t, err)
+
+	err = config.Process()
+	require.NoError(t, err)
+
+	require.Equal(t, "secret", config.Database.Pass)
+
+	logger := &logur.TestLoggerFacade{}
+
+	reloader := newConfigReloader(v, config, logger)
+
+	var applied configuration
+
+	reloader.OnReload(func(_ configuration, newConfig configuration) error {
+		applied = newConfig
+
+		return nil
+	})
+
+	err = ioutil.WriteFile(path, []byte("rotated"), 0600)
+	require.NoError(t, err)
+
+	err = reloader.Reload()
+	require.NoError(t, err)
+
+	assert.Equal(t, "rotated", applied.Database.Pass)
+	assert.Equal(t, 0, logger.Count(), "rotating a secret should not be reported as an ignored change")
+}
+
+func TestConfigReloader_IgnoredKeys(t *testing.T) {
+	v := viper.New()
+	p := pflag.NewFlagSet("test", pflag.ContinueOnError)
+
+	configure(v, p)
+
+	file, err := os.Open("../../config.toml.dist")
+	require.NoError(t, err)
+	defer file.Close()
+
+	v.SetConfigType("toml")
+
+	err = v.ReadConfig(file)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "host")
+
+	err = ioutil.WriteFile(path, []byte("localhost"), 0600)
+	require.NoError(t, err)
+
+	v.Set("database.host", "file://"+path)
+
+	var config configuration
+
+	err = v.Unmarshal(&config)
+	require.NoError(t, err)
+
+	err = config.Process()
+	require.NoError(t, err)
+
+	logger := &logur.TestLoggerFacade{}
+
+	reloader := newConfigReloader(v, config, logger)
+
+	var applied configuration
+
+	reloader.OnReload(func(_ configuration, newConfig configuration) error {
+		applied = newConfig
+
+		return nil
+	})
+
+	v.Set("errorHandler.handlers", []string{"log", "file"})
+	v.Set("healthcheck.disk.enabled", true)
+	v.Set("healthcheck.period", "10s")
+
+	err = ioutil.WriteFile(path, []byte("db.example.com"), 0600)
+	require.NoError(t, err)
+
+	err = reloader.Reload()
+	require.NoError(t, err)
+
+	assert.Equal(t, config.ErrorHandler.Handlers, applied.ErrorHandler.Handlers)
+	assert.Equal(t, config.Healthcheck.Disk, applied.Healthcheck.Disk)
+	assert.Equal(t, config.Database.Host, applied.Database.Host)
+
+	var keys []string
+
+	for _, event := range logger.Events() {
+		assert.Equal(t, logur.Warn, event.Level)
+
+		keys = append(keys, event.Fields["key"].(string))
+	}
+
+	assert.ElementsMatch(t, []string{"errorHandler.handlers", "healthcheck.disk.enabled", "database.host"}, keys)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/oklog/run"
+	"logur.dev/logur"
+
+	"github.com/sagikazarmark/modern-go-application/internal/platform/tlsconfig"
+)
+
+// configureTLS returns the TLS configuration of a server (or nil if TLS is disabled)
+// and adds an actor to the run group reloading certificates when they change.
+func configureTLS(group *run.Group, config tlsconfig.Config, logger logur.Logger) (*tls.Config, error) {
+	if !config.Enabled {
+		return nil, nil
+	}
+
+	server, err := tlsconfig.NewServer(config, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	group.Add(func() error { return server.Watch(ctx) }, func(error) { cancel() })
+
+	return server.TLSConfig(), nil
+}
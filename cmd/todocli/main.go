@@ -1,25 +1,28 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 
 	"github.com/sagikazarmark/modern-go-application/internal/app/todocli"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/command"
 )
 
 func main() {
 	rootCmd := &cobra.Command{
 		Use:   "todocli",
 		Short: "TODO CLI manages TODOs.",
+
+		// Errors are rendered by PrintError below
+		SilenceErrors: true,
 	}
 
 	todocli.Configure(rootCmd)
 
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		command.PrintError(os.Stderr, err)
 
-		os.Exit(1)
+		os.Exit(command.ExitCode(err))
 	}
 }
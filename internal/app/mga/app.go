@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"net/http"
 
+	"emperror.dev/errors"
+	"entgo.io/ent/dialect"
 	entsql "entgo.io/ent/dialect/sql"
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/ThreeDotsLabs/watermill/components/cqrs"
@@ -28,6 +30,7 @@ import (
 	"google.golang.org/grpc"
 	watermilllog "logur.dev/integration/watermill"
 
+	"github.com/sagikazarmark/modern-go-application/api/openapi"
 	"github.com/sagikazarmark/modern-go-application/internal/app/mga/httpbin"
 	"github.com/sagikazarmark/modern-go-application/internal/app/mga/landing/landingdriver"
 	todo2 "github.com/sagikazarmark/modern-go-application/internal/app/mga/todo"
@@ -36,18 +39,45 @@ import (
 	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/todoadapter/ent/migrate"
 	tododriver2 "github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/tododriver"
 	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/todogen"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/openapivalidator"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/ratelimit"
+	"github.com/sagikazarmark/modern-go-application/static/swaggerui"
 	"github.com/sagikazarmark/modern-go-application/static/templates"
 )
 
-const todoTopic = "todo"
+// TodoTopic is the topic todo events are published to.
+const TodoTopic = "todo"
+
+// NewTodoStore returns a new todo store.
+//
+// Storage is either inmemory or database (a MySQL database, its schema is migrated).
+func NewTodoStore(storage string, db *sql.DB) (todo.Store, error) {
+	if storage != "database" {
+		return todo.NewInMemoryStore(), nil
+	}
+
+	client := ent.NewClient(ent.Driver(entsql.OpenDB(dialect.MySQL, db)))
+	err := client.Schema.Create(
+		context.Background(),
+		migrate.WithDropIndex(true),
+		migrate.WithDropColumn(true),
+	)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to migrate database schema")
+	}
+
+	return todoadapter.NewEntStore(client), nil
+}
 
 // InitializeApp initializes a new HTTP and a new gRPC application.
+// The content security policy middleware is applied to the HTML pages with a policy of their own (the landing page).
 func InitializeApp(
 	httpRouter *mux.Router,
+	contentSecurityPolicy mux.MiddlewareFunc,
 	grpcServer *grpc.Server,
 	publisher message.Publisher,
-	storage string,
-	db *sql.DB,
+	store todo.Store,
+	rateLimiter *ratelimit.Limiter,
 	logger Logger,
 	errorHandler ErrorHandler, // nolint: interfacer
 ) {
@@ -59,43 +89,37 @@ func InitializeApp(
 			return name
 		})),
 		appkitendpoint.LoggingMiddleware(logger),
+		ratelimit.Middleware(rateLimiter),
 	}
 
 	transportErrorHandler := kitxtransport.NewErrorHandler(errorHandler)
+	httpErrorEncoder := kitxhttp.NewJSONProblemErrorEncoder(appkithttp.NewDefaultProblemConverter(
+		appkithttp.WithProblemMatchers(
+			appkithttp.NewStatusProblemMatcher(http.StatusTooManyRequests, ratelimit.IsLimitExceededError),
+		),
+	))
 
 	httpServerOptions := []kithttp.ServerOption{
 		kithttp.ServerErrorHandler(transportErrorHandler),
-		kithttp.ServerErrorEncoder(kitxhttp.NewJSONProblemErrorEncoder(appkithttp.NewDefaultProblemConverter())),
+		kithttp.ServerErrorEncoder(httpErrorEncoder),
 		kithttp.ServerBefore(correlation.HTTPToContext(), kithttp.PopulateRequestContext),
 	}
 
 	grpcServerOptions := []kitgrpc.ServerOption{
 		kitgrpc.ServerErrorHandler(transportErrorHandler),
-		kitgrpc.ServerBefore(correlation.GRPCToContext()),
+		kitgrpc.ServerBefore(correlation.GRPCToContext(), ratelimit.GRPCToContext(rateLimiter)),
 	}
 
+	// Identify clients of every HTTP API (REST, JSON/REST gateway, GraphQL) for rate limiting
+	httpRouter.Use(ratelimit.HTTPMiddleware(rateLimiter))
+
 	{
 		eventBus, _ := cqrs.NewEventBus(
 			publisher,
-			func(eventName string) string { return todoTopic },
+			func(eventName string) string { return TodoTopic },
 			cqrs.JSONMarshaler{GenerateName: cqrs.StructName},
 		)
 
-		var store todo.Store = todo.NewInMemoryStore()
-		if storage == "database" {
-			client := ent.NewClient(ent.Driver(entsql.OpenDB("mysql", db)))
-			err := client.Schema.Create(
-				context.Background(),
-				migrate.WithDropIndex(true),
-				migrate.WithDropColumn(true),
-			)
-			if err != nil {
-				panic(err)
-			}
-
-			store = todoadapter.NewEntStore(client)
-		}
-
 		service := todo.NewService(ulidgen.NewGenerator(), store)
 		service = todo2.EventMiddleware(todogen.NewEventDispatcher(eventBus))(service)
 		service = tododriver2.LoggingMiddleware(logger)(service)
@@ -106,21 +130,61 @@ func InitializeApp(
 			kitxendpoint.Combine(endpointMiddleware...),
 		)
 
+		// Requests are validated against the OpenAPI document before reaching the endpoints
+		todoDocument, err := openapivalidator.Load(openapi.Files(), "todo.yaml")
+		if err != nil {
+			panic(err)
+		}
+
+		err = tododriver2.AdaptOpenAPIDocument(todoDocument)
+		if err != nil {
+			panic(err)
+		}
+
+		todoValidator, err := openapivalidator.Middleware(todoDocument, httpErrorEncoder)
+		if err != nil {
+			panic(err)
+		}
+
+		todoRouter := httpRouter.PathPrefix("/todos").Subrouter()
+		todoRouter.Use(todoValidator)
+
 		tododriver.RegisterHTTPHandlers(
 			endpoints,
-			httpRouter.PathPrefix("/todos").Subrouter(),
+			todoRouter,
 			kitxhttp.ServerOptions(httpServerOptions),
 		)
-		todov1.RegisterTodoListServiceServer(
-			grpcServer,
-			tododriver.MakeGRPCServer(endpoints, kitxgrpc.ServerOptions(grpcServerOptions)),
+		httpRouter.Path("/openapi.json").Methods(http.MethodGet).Handler(
+			tododriver2.OpenAPIDocumentHandler(todoDocument),
 		)
-		httpRouter.PathPrefix("/graphql").Handler(handler.NewDefaultServer(
-			tododriver.MakeGraphQLSchema(endpoints),
-		))
+
+		grpcTodoServer := tododriver.MakeGRPCServer(endpoints, kitxgrpc.ServerOptions(grpcServerOptions))
+		todov1.RegisterTodoListServiceServer(grpcServer, grpcTodoServer)
+
+		// JSON/REST gateway to the gRPC API
+		gatewayMux := tododriver2.NewGatewayServeMux()
+		err = tododriver2.RegisterGatewayHandlers(gatewayMux, grpcTodoServer)
+		if err != nil {
+			panic(err)
+		}
+
+		httpRouter.Path("/api/v1/openapi.json").Methods(http.MethodGet).Handler(
+			tododriver2.OpenAPIHandler(openapi.Files(), "todo_list.swagger.json"),
+		)
+		httpRouter.PathPrefix("/api/v1/").Handler(gatewayMux)
+
+		graphqlServer := handler.NewDefaultServer(tododriver.MakeGraphQLSchema(endpoints))
+		graphqlServer.SetErrorPresenter(tododriver2.GraphQLErrorPresenter())
+
+		httpRouter.PathPrefix("/graphql").Handler(graphqlServer)
 	}
 
-	landingdriver.RegisterHTTPHandlers(httpRouter, templates.Files())
+	landingdriver.RegisterHTTPHandlers(httpRouter, templates.Files(), contentSecurityPolicy)
+	httpRouter.Path("/docs").Methods(http.MethodGet).Handler(http.RedirectHandler("/docs/", http.StatusMovedPermanently))
+	httpRouter.PathPrefix("/docs/").Methods(http.MethodGet).Handler(http.StripPrefix(
+		"/docs",
+		http.FileServer(http.FS(swaggerui.Files())),
+	))
 	httpRouter.PathPrefix("/httpbin").Handler(http.StripPrefix(
 		"/httpbin",
 		httpbin.MakeHTTPHandler(logger.WithFields(map[string]interface{}{"module": "httpbin"})),
@@ -133,7 +197,7 @@ func RegisterEventHandlers(router *message.Router, subscriber message.Subscriber
 		[]cqrs.EventHandler{
 			todogen.NewMarkedAsCompleteEventHandler(todo2.NewLogEventHandler(logger), "marked_as_complete"),
 		},
-		func(eventName string) string { return todoTopic },
+		func(eventName string) string { return TodoTopic },
 		func(handlerName string) (message.Subscriber, error) { return subscriber, nil },
 		cqrs.JSONMarshaler{GenerateName: cqrs.StructName},
 		watermilllog.New(logger.WithFields(map[string]interface{}{"component": "watermill"})),
@@ -0,0 +1,336 @@
+package mga_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga/mgatest"
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo"
+)
+
+func TestTodo(t *testing.T) {
+	for _, storage := range []string{mgatest.StorageInMemory, mgatest.StorageSQLite} {
+		storage := storage
+
+		t.Run(storage, func(t *testing.T) {
+			t.Run("GRPC", func(t *testing.T) {
+				testTodoGRPC(t, mgatest.NewServer(t, mgatest.Config{Storage: storage}))
+			})
+
+			t.Run("HTTP", func(t *testing.T) {
+				testTodoHTTP(t, mgatest.NewServer(t, mgatest.Config{Storage: storage}))
+			})
+
+			t.Run("GraphQL", func(t *testing.T) {
+				testTodoGraphQL(t, mgatest.NewServer(t, mgatest.Config{Storage: storage}))
+			})
+		})
+	}
+}
+
+// assertMarkedAsComplete asserts that a MarkedAsComplete event is published and handled.
+func assertMarkedAsComplete(t *testing.T, server *mgatest.Server, id string) {
+	t.Helper()
+
+	var event todo.MarkedAsComplete
+
+	require.NoError(t, json.Unmarshal(server.WaitForEvent(t, "MarkedAsComplete").Payload, &event))
+	assert.Equal(t, id, event.ID)
+
+	logEvent := server.WaitForLog(t, "todo marked as complete")
+	assert.Equal(t, id, logEvent.Fields["todo_id"])
+}
+
+func testTodoGRPC(t *testing.T, server *mgatest.Server) {
+	client := todov1.NewTodoListServiceClient(server.GRPCConn)
+	ctx := context.Background()
+
+	addResp, err := client.AddItem(ctx, &todov1.AddItemRequest{Title: "Buy milk", Order: 1})
+	require.NoError(t, err)
+
+	item := addResp.GetItem()
+	require.NotEmpty(t, item.GetId())
+	assert.Equal(t, "Buy milk", item.GetTitle())
+	assert.Equal(t, int32(1), item.GetOrder())
+	assert.False(t, item.GetCompleted())
+
+	_, err = client.AddItem(ctx, &todov1.AddItemRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	listResp, err := client.ListItems(ctx, &todov1.ListItemsRequest{})
+	require.NoError(t, err)
+	require.Len(t, listResp.GetItems(), 1)
+	assert.Equal(t, item.GetId(), listResp.GetItems()[0].GetId())
+
+	getResp, err := client.GetItem(ctx, &todov1.GetItemRequest{Id: item.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, "Buy milk", getResp.GetItem().GetTitle())
+
+	_, err = client.GetItem(ctx, &todov1.GetItemRequest{Id: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	updateResp, err := client.UpdateItem(ctx, &todov1.UpdateItemRequest{
+		Id:    item.GetId(),
+		Title: &wrappers.StringValue{Value: "Buy oat milk"},
+		Order: &wrappers.Int32Value{Value: 2},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Buy oat milk", updateResp.GetItem().GetTitle())
+	assert.Equal(t, int32(2), updateResp.GetItem().GetOrder())
+	assert.False(t, updateResp.GetItem().GetCompleted())
+	assert.Empty(t, server.Events())
+
+	updateResp, err = client.UpdateItem(ctx, &todov1.UpdateItemRequest{
+		Id:        item.GetId(),
+		Completed: &wrappers.BoolValue{Value: true},
+	})
+	require.NoError(t, err)
+	assert.True(t, updateResp.GetItem().GetCompleted())
+	assert.Equal(t, "Buy oat milk", updateResp.GetItem().GetTitle())
+
+	assertMarkedAsComplete(t, server, item.GetId())
+
+	_, err = client.UpdateItem(ctx, &todov1.UpdateItemRequest{Id: "unknown", Completed: &wrappers.BoolValue{Value: true}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.DeleteItem(ctx, &todov1.DeleteItemRequest{Id: item.GetId()})
+	require.NoError(t, err)
+
+	_, err = client.GetItem(ctx, &todov1.GetItemRequest{Id: item.GetId()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Deleting is idempotent
+	_, err = client.DeleteItem(ctx, &todov1.DeleteItemRequest{Id: item.GetId()})
+	assert.NoError(t, err)
+
+	for _, title := range []string{"Call mom", "Walk the dog"} {
+		_, err = client.AddItem(ctx, &todov1.AddItemRequest{Title: title})
+		require.NoError(t, err)
+	}
+
+	_, err = client.DeleteItems(ctx, &todov1.DeleteItemsRequest{})
+	require.NoError(t, err)
+
+	listResp, err = client.ListItems(ctx, &todov1.ListItemsRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, listResp.GetItems())
+}
+
+type httpTodoItem struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+	Order     int32  `json:"order"`
+	URL       string `json:"url"`
+}
+
+// doHTTP sends a JSON request to the HTTP server, decodes the response into out (if any) and returns the status code.
+func doHTTP(t *testing.T, server *mgatest.Server, method string, path string, body interface{}, out interface{}) int {
+	t.Helper()
+
+	var reqBody bytes.Buffer
+
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&reqBody).Encode(body))
+	}
+
+	req, err := http.NewRequest(method, server.URL+path, &reqBody)
+	require.NoError(t, err)
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := server.HTTPClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if out != nil && resp.StatusCode < http.StatusMultipleChoices {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+
+	return resp.StatusCode
+}
+
+func testTodoHTTP(t *testing.T, server *mgatest.Server) {
+	var item httpTodoItem
+
+	code := doHTTP(t, server, http.MethodPost, "/todos", map[string]interface{}{"title": "Buy milk", "order": 1}, &item)
+	require.Equal(t, http.StatusCreated, code)
+
+	require.NotEmpty(t, item.ID)
+	assert.Equal(t, "Buy milk", item.Title)
+	assert.Equal(t, int32(1), item.Order)
+	assert.False(t, item.Completed)
+	assert.True(t, strings.HasSuffix(item.URL, "/"+item.ID), item.URL)
+
+	code = doHTTP(t, server, http.MethodPost, "/todos", map[string]interface{}{"title": "", "order": 0}, nil)
+	assert.Equal(t, http.StatusUnprocessableEntity, code)
+
+	var items []httpTodoItem
+
+	require.Equal(t, http.StatusOK, doHTTP(t, server, http.MethodGet, "/todos", nil, &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, item.ID, items[0].ID)
+
+	var got httpTodoItem
+
+	require.Equal(t, http.StatusOK, doHTTP(t, server, http.MethodGet, "/todos/"+item.ID, nil, &got))
+	assert.Equal(t, item, got)
+
+	assert.Equal(t, http.StatusNotFound, doHTTP(t, server, http.MethodGet, "/todos/unknown", nil, nil))
+
+	var updated httpTodoItem
+
+	update := map[string]interface{}{"title": "Buy oat milk"}
+
+	code = doHTTP(t, server, http.MethodPatch, "/todos/"+item.ID, update, &updated)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "Buy oat milk", updated.Title)
+	assert.False(t, updated.Completed)
+	assert.Empty(t, server.Events())
+
+	code = doHTTP(t, server, http.MethodPatch, "/todos/"+item.ID, map[string]interface{}{"completed": true}, &updated)
+	require.Equal(t, http.StatusOK, code)
+	assert.True(t, updated.Completed)
+	assert.Equal(t, "Buy oat milk", updated.Title)
+
+	assertMarkedAsComplete(t, server, item.ID)
+
+	code = doHTTP(t, server, http.MethodPatch, "/todos/unknown", map[string]interface{}{"completed": true}, nil)
+	assert.Equal(t, http.StatusNotFound, code)
+
+	require.Equal(t, http.StatusNoContent, doHTTP(t, server, http.MethodDelete, "/todos/"+item.ID, nil, nil))
+	assert.Equal(t, http.StatusNotFound, doHTTP(t, server, http.MethodGet, "/todos/"+item.ID, nil, nil))
+
+	// Deleting is idempotent
+	assert.Equal(t, http.StatusNoContent, doHTTP(t, server, http.MethodDelete, "/todos/"+item.ID, nil, nil))
+
+	for _, title := range []string{"Call mom", "Walk the dog"} {
+		code = doHTTP(t, server, http.MethodPost, "/todos", map[string]interface{}{"title": title, "order": 0}, nil)
+		require.Equal(t, http.StatusCreated, code)
+	}
+
+	require.Equal(t, http.StatusNoContent, doHTTP(t, server, http.MethodDelete, "/todos", nil, nil))
+
+	require.Equal(t, http.StatusOK, doHTTP(t, server, http.MethodGet, "/todos", nil, &items))
+	assert.Empty(t, items)
+}
+
+type graphqlError struct {
+	Message    string                 `json:"message"`
+	Extensions map[string]interface{} `json:"extensions"`
+}
+
+// doGraphQL sends a GraphQL request, decodes the data of the response into out and returns the errors.
+func doGraphQL(
+	t *testing.T,
+	server *mgatest.Server,
+	query string,
+	variables map[string]interface{},
+	out interface{},
+) []graphqlError {
+	t.Helper()
+
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	require.NoError(t, err)
+
+	resp, err := server.HTTPClient.Post(server.GraphQLURL(), "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphqlError  `json:"errors"`
+	}
+
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&result))
+
+	if len(result.Errors) == 0 && out != nil {
+		require.NoError(t, json.Unmarshal(result.Data, out))
+	}
+
+	return result.Errors
+}
+
+type graphqlTodoItem struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+	Order     int32  `json:"order"`
+}
+
+func testTodoGraphQL(t *testing.T, server *mgatest.Server) {
+	const (
+		addQuery    = "mutation($input: NewTodoItem!) { addTodoItem(input: $input) { id title completed order } }"
+		updateQuery = "mutation($input: TodoItemUpdate!) { updateTodoItem(input: $input) { id title completed order } }"
+		listQuery   = "query { todoItems { id title completed order } }"
+	)
+
+	var added struct {
+		AddTodoItem graphqlTodoItem `json:"addTodoItem"`
+	}
+
+	errs := doGraphQL(t, server, addQuery, map[string]interface{}{
+		"input": map[string]interface{}{"title": "Buy milk", "order": 1},
+	}, &added)
+	require.Empty(t, errs)
+
+	item := added.AddTodoItem
+	require.NotEmpty(t, item.ID)
+	assert.Equal(t, "Buy milk", item.Title)
+	assert.Equal(t, int32(1), item.Order)
+	assert.False(t, item.Completed)
+
+	errs = doGraphQL(t, server, addQuery, map[string]interface{}{
+		"input": map[string]interface{}{"title": ""},
+	}, nil)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "INVALID_ARGUMENT", errs[0].Extensions["code"])
+
+	var list struct {
+		TodoItems []graphqlTodoItem `json:"todoItems"`
+	}
+
+	require.Empty(t, doGraphQL(t, server, listQuery, nil, &list))
+	assert.Equal(t, []graphqlTodoItem{item}, list.TodoItems)
+
+	var updated struct {
+		UpdateTodoItem graphqlTodoItem `json:"updateTodoItem"`
+	}
+
+	errs = doGraphQL(t, server, updateQuery, map[string]interface{}{
+		"input": map[string]interface{}{"id": item.ID, "title": "Buy oat milk", "order": 2},
+	}, &updated)
+	require.Empty(t, errs)
+	assert.Equal(t, graphqlTodoItem{ID: item.ID, Title: "Buy oat milk", Order: 2}, updated.UpdateTodoItem)
+	assert.Empty(t, server.Events())
+
+	errs = doGraphQL(t, server, updateQuery, map[string]interface{}{
+		"input": map[string]interface{}{"id": item.ID, "completed": true},
+	}, &updated)
+	require.Empty(t, errs)
+	assert.Equal(t, graphqlTodoItem{ID: item.ID, Title: "Buy oat milk", Completed: true, Order: 2}, updated.UpdateTodoItem)
+
+	assertMarkedAsComplete(t, server, item.ID)
+
+	errs = doGraphQL(t, server, updateQuery, map[string]interface{}{
+		"input": map[string]interface{}{"id": "unknown", "completed": true},
+	}, nil)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "NOT_FOUND", errs[0].Extensions["code"])
+
+	require.Empty(t, doGraphQL(t, server, listQuery, nil, &list))
+	assert.Equal(t, []graphqlTodoItem{updated.UpdateTodoItem}, list.TodoItems)
+}
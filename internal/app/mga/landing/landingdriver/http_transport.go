@@ -9,8 +9,15 @@ import (
 )
 
 // RegisterHTTPHandlers mounts the HTTP handler for the landing page in a router.
-func RegisterHTTPHandlers(router *mux.Router, fsys fs.FS) {
-	router.Path("/").Methods(http.MethodGet).Handler(Landing(fsys))
+// Middleware (eg. a content security policy) is applied to the landing page only.
+func RegisterHTTPHandlers(router *mux.Router, fsys fs.FS, middleware ...mux.MiddlewareFunc) {
+	handler := Landing(fsys)
+
+	for i := len(middleware) - 1; i >= 0; i-- {
+		handler = middleware[i](handler)
+	}
+
+	router.Path("/").Methods(http.MethodGet).Handler(handler)
 }
 
 // Landing is the landing page for Modern Go Application.
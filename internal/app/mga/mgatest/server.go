@@ -0,0 +1,259 @@
+// Package mgatest runs the application in-process for end-to-end tests.
+//
+// A test server wires the application the same way the binary does (see mga.InitializeApp):
+// the HTTP router (REST API, JSON/REST gateway and GraphQL), the gRPC server and the event handlers.
+package mgatest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/gorilla/mux"
+	_ "github.com/mattn/go-sqlite3" // SQLite storage
+	"github.com/sagikazarmark/todobackend-go-kit/todo"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+	"logur.dev/logur"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga"
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/todoadapter"
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/todoadapter/ent"
+	"github.com/sagikazarmark/modern-go-application/internal/common"
+	"github.com/sagikazarmark/modern-go-application/internal/common/commonadapter"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/ratelimit"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/securityheaders"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/watermill"
+)
+
+// Storage backends of a test server.
+const (
+	StorageInMemory = "inmemory"
+	StorageSQLite   = "sqlite"
+)
+
+// waitTimeout is the time to wait for asynchronous events (eg. event handlers).
+const waitTimeout = 5 * time.Second
+
+// Config configures a test server.
+type Config struct {
+	// Storage is the storage of the application: inmemory (default) or sqlite (a new SQLite database).
+	Storage string
+}
+
+// Event is an event published by the application.
+type Event struct {
+	// Name of the event (eg. MarkedAsComplete).
+	Name string
+
+	// Payload of the event (JSON).
+	Payload json.RawMessage
+}
+
+// Server is an in-process instance of the application.
+// Servers are stopped when the test finishes.
+type Server struct {
+	// URL of the HTTP server.
+	URL string
+
+	// HTTPClient sends requests to the HTTP server.
+	HTTPClient *http.Client
+
+	// GRPCConn is a client connection to the gRPC server.
+	GRPCConn *grpc.ClientConn
+
+	// Logs records the log events of the application (including event handlers).
+	Logs *logur.TestLoggerFacade
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewServer starts a new test server.
+func NewServer(t testing.TB, config Config) *Server {
+	t.Helper()
+
+	if config.Storage == "" {
+		config.Storage = StorageInMemory
+	}
+
+	s := &Server{
+		Logs: &logur.TestLoggerFacade{},
+	}
+
+	logger := commonadapter.NewLogger(s.Logs)
+
+	var store todo.Store = todo.NewInMemoryStore()
+	if config.Storage == StorageSQLite {
+		store = newSQLiteStore(t)
+	}
+
+	publisher, subscriber := watermill.NewPubSub(logur.NoopLogger{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	// Events are recorded before anything is published
+	messages, err := subscriber.Subscribe(ctx, mga.TodoTopic)
+	require.NoError(t, err)
+
+	go s.recordEvents(messages)
+
+	httpRouter := mux.NewRouter()
+	grpcServer := grpc.NewServer()
+
+	mga.InitializeApp(
+		httpRouter,
+		securityheaders.NewHandler(securityheaders.Config{}).ContentSecurityPolicy,
+		grpcServer,
+		publisher,
+		store,
+		ratelimit.NewLimiter(ratelimit.Config{}),
+		logger,
+		common.NoopErrorHandler{},
+	)
+
+	eventRouter, err := watermill.NewRouter(logur.NoopLogger{})
+	require.NoError(t, err)
+
+	require.NoError(t, mga.RegisterEventHandlers(eventRouter, subscriber, logger))
+
+	go func() { _ = eventRouter.Run(ctx) }()
+	t.Cleanup(func() { _ = watermill.CloseRouter(context.Background(), eventRouter) })
+	<-eventRouter.Running()
+
+	httpServer := httptest.NewServer(httpRouter)
+	t.Cleanup(httpServer.Close)
+
+	s.URL = httpServer.URL
+	s.HTTPClient = httpServer.Client()
+
+	listener := bufconn.Listen(1024 * 1024)
+
+	go func() { _ = grpcServer.Serve(listener) }()
+	t.Cleanup(grpcServer.Stop)
+
+	s.GRPCConn, err = grpc.DialContext(
+		ctx,
+		"bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithInsecure(),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = s.GRPCConn.Close() })
+
+	return s
+}
+
+// newSQLiteStore returns a todo store backed by a new SQLite database.
+func newSQLiteStore(t testing.TB) todo.Store {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "mga.db")+"?_fk=1")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	// SQLite does not support concurrent writes
+	db.SetMaxOpenConns(1)
+
+	client := ent.NewClient(ent.Driver(entsql.OpenDB(dialect.SQLite, db)))
+	require.NoError(t, client.Schema.Create(context.Background()))
+
+	return todoadapter.NewEntStore(client)
+}
+
+func (s *Server) recordEvents(messages <-chan *message.Message) {
+	marshaler := cqrs.JSONMarshaler{GenerateName: cqrs.StructName}
+
+	for msg := range messages {
+		s.mu.Lock()
+		s.events = append(s.events, Event{
+			Name:    marshaler.NameFromMessage(msg),
+			Payload: json.RawMessage(msg.Payload),
+		})
+		s.mu.Unlock()
+
+		msg.Ack()
+	}
+}
+
+// GraphQLURL returns the URL of the GraphQL endpoint.
+func (s *Server) GraphQLURL() string {
+	return s.URL + "/graphql"
+}
+
+// Events returns the events published by the application so far.
+func (s *Server) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Event(nil), s.events...)
+}
+
+// WaitForEvent waits for an event to be published and returns the first event with the name.
+func (s *Server) WaitForEvent(t testing.TB, name string) Event {
+	t.Helper()
+
+	var event Event
+
+	waitFor(t, "event "+name, func() bool {
+		for _, e := range s.Events() {
+			if e.Name == name {
+				event = e
+
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return event
+}
+
+// WaitForLog waits for the application to log a message and returns the first log event with the message.
+func (s *Server) WaitForLog(t testing.TB, msg string) logur.LogEvent {
+	t.Helper()
+
+	var event logur.LogEvent
+
+	waitFor(t, "log message "+msg, func() bool {
+		for _, e := range s.Logs.Events() {
+			if e.Line == msg {
+				event = e
+
+				return true
+			}
+		}
+
+		return false
+	})
+
+	return event
+}
+
+func waitFor(t testing.TB, what string, condition func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(waitTimeout)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -22,41 +22,53 @@ func NewEntStore(client *ent.Client) todo.Store {
 	}
 }
 
+// Store creates an item or updates an existing one.
 func (s entStore) Store(ctx context.Context, todo todo.Item) error {
-	existing, err := s.client.TodoItem.Query().Where(todoitem.UID(todo.ID)).First(ctx)
-	if ent.IsNotFound(err) {
-		_, err := s.client.TodoItem.Create().
-			SetUID(todo.ID).
-			SetTitle(todo.Title).
-			SetCompleted(todo.Completed).
-			SetOrder(todo.Order).
-			Save(ctx)
-		if err != nil {
-			return err
-		}
-
-		return nil
-	}
-	if err != nil {
+	// Updating first makes concurrent writes of a new item safe:
+	// when another write creates the item first, creating it fails and it is updated instead.
+	updated, err := s.update(ctx, todo)
+	if err != nil || updated {
 		return err
 	}
 
-	_, err = s.client.TodoItem.UpdateOneID(existing.ID).
+	_, err = s.client.TodoItem.Create().
+		SetUID(todo.ID).
 		SetTitle(todo.Title).
 		SetCompleted(todo.Completed).
 		SetOrder(todo.Order).
 		Save(ctx)
-	if err != nil {
+	if ent.IsConstraintError(err) {
+		_, err = s.update(ctx, todo)
+
 		return err
 	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
 
 	return nil
 }
 
+// update updates an existing item and reports whether it exists.
+func (s entStore) update(ctx context.Context, todo todo.Item) (bool, error) {
+	n, err := s.client.TodoItem.Update().
+		Where(todoitem.UID(todo.ID)).
+		SetTitle(todo.Title).
+		SetCompleted(todo.Completed).
+		SetOrder(todo.Order).
+		Save(ctx)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+
+	return n > 0, nil
+}
+
+// GetAll returns every item ordered by ID (like the in-memory store).
 func (s entStore) GetAll(ctx context.Context) ([]todo.Item, error) {
-	todoModels, err := s.client.TodoItem.Query().All(ctx)
+	todoModels, err := s.client.TodoItem.Query().Order(ent.Asc(todoitem.FieldUID)).All(ctx)
 	if err != nil {
-		return nil, err
+		return nil, errors.WithStack(err)
 	}
 
 	todos := make([]todo.Item, 0, len(todoModels))
@@ -78,6 +90,9 @@ func (s entStore) GetOne(ctx context.Context, id string) (todo.Item, error) {
 	if ent.IsNotFound(err) {
 		return todo.Item{}, errors.WithStack(todo.NotFoundError{ID: id})
 	}
+	if err != nil {
+		return todo.Item{}, errors.WithStack(err)
+	}
 
 	return todo.Item{
 		ID:        todoModel.UID,
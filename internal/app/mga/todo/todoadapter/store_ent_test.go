@@ -0,0 +1,89 @@
+package todoadapter
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"emperror.dev/errors"
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sagikazarmark/todobackend-go-kit/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/todoadapter/ent"
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/todoadapter/ent/enttest"
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/todoadapter/ent/hook"
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga/todo/todotest"
+)
+
+// newTestClient returns a client of a new SQLite database.
+func newTestClient(t *testing.T) *ent.Client {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "todo.db")+"?_fk=1")
+	require.NoError(t, err)
+
+	// SQLite does not support concurrent writes
+	db.SetMaxOpenConns(1)
+
+	client := enttest.NewClient(t, enttest.WithOptions(ent.Driver(entsql.OpenDB(dialect.SQLite, db))))
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func TestEntStore(t *testing.T) {
+	todotest.RunStoreTests(t, func(t *testing.T) todo.Store {
+		return NewEntStore(newTestClient(t))
+	})
+}
+
+func TestEntStore_Store_CreatedConcurrently(t *testing.T) {
+	client := newTestClient(t)
+	store := NewEntStore(client)
+
+	var created bool
+
+	// Another write creates the item after the store found that it does not exist
+	client.TodoItem.Use(hook.On(func(next ent.Mutator) ent.Mutator {
+		return hook.TodoItemFunc(func(ctx context.Context, m *ent.TodoItemMutation) (ent.Value, error) {
+			if !created {
+				created = true
+
+				_, err := client.TodoItem.Create().
+					SetUID("item-1").
+					SetTitle("Buy milk").
+					SetCompleted(false).
+					SetOrder(1).
+					Save(ctx)
+				require.NoError(t, err)
+			}
+
+			return next.Mutate(ctx, m)
+		})
+	}, ent.OpCreate))
+
+	item := todo.Item{ID: "item-1", Title: "Buy oat milk", Completed: true, Order: 2}
+
+	require.NoError(t, store.Store(context.Background(), item))
+	require.True(t, created)
+
+	items, err := store.GetAll(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []todo.Item{item}, items)
+}
+
+func TestEntStore_GetOne_Error(t *testing.T) {
+	client := newTestClient(t)
+	store := NewEntStore(client)
+
+	require.NoError(t, client.Close())
+
+	_, err := store.GetOne(context.Background(), "item-1")
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &todo.NotFoundError{}))
+}
@@ -0,0 +1,73 @@
+package tododriver
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/99designs/gqlgen/graphql"
+	appkitgrpc "github.com/sagikazarmark/appkit/transport/grpc"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+)
+
+// GraphQLErrorPresenter returns a GraphQL error presenter exposing the gRPC status code of errors
+// as the "code" extension (eg. NOT_FOUND) and validation violations as the "violations" extension,
+// so that clients can tell errors apart without parsing messages.
+func GraphQLErrorPresenter() graphql.ErrorPresenterFunc {
+	converter := appkitgrpc.NewDefaultStatusConverter()
+
+	return func(ctx context.Context, err error) *gqlerror.Error {
+		gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+		// Errors of the query itself (eg. syntax errors) are not returned by the service and have their own codes
+		cause := gqlErr.Unwrap()
+		if cause == nil {
+			return gqlErr
+		}
+
+		st := converter.NewStatus(ctx, cause)
+
+		if gqlErr.Extensions == nil {
+			gqlErr.Extensions = make(map[string]interface{})
+		}
+
+		gqlErr.Extensions["code"] = graphQLErrorCode(st.Code())
+
+		violations := make(map[string][]string)
+
+		for _, detail := range st.Details() {
+			if br, ok := detail.(*errdetails.BadRequest); ok {
+				for _, violation := range br.GetFieldViolations() {
+					violations[violation.GetField()] = append(violations[violation.GetField()], violation.GetDescription())
+				}
+			}
+		}
+
+		if len(violations) > 0 {
+			gqlErr.Extensions["violations"] = violations
+		}
+
+		return gqlErr
+	}
+}
+
+// graphQLErrorCode returns the name of a status code in the format used by
+// the gRPC specification and GraphQL servers (eg. NotFound becomes NOT_FOUND).
+func graphQLErrorCode(code codes.Code) string {
+	var name strings.Builder
+
+	previous := ' '
+
+	for _, r := range code.String() {
+		if unicode.IsUpper(r) && unicode.IsLower(previous) {
+			name.WriteRune('_')
+		}
+
+		name.WriteRune(unicode.ToUpper(r))
+		previous = r
+	}
+
+	return name.String()
+}
@@ -0,0 +1,63 @@
+package tododriver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/goph/idgen/ulidgen"
+	"github.com/sagikazarmark/todobackend-go-kit/todo"
+	kittododriver "github.com/sagikazarmark/todobackend-go-kit/todo/tododriver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGraphQLErrorPresenter(t *testing.T) {
+	service := todo.NewService(ulidgen.NewGenerator(), todo.NewInMemoryStore())
+
+	server := handler.NewDefaultServer(kittododriver.MakeGraphQLSchema(kittododriver.MakeEndpoints(service)))
+	server.SetErrorPresenter(GraphQLErrorPresenter())
+
+	do := func(query string) map[string]interface{} {
+		body, err := json.Marshal(map[string]string{"query": query})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		server.ServeHTTP(rec, req)
+
+		var resp struct {
+			Errors []struct {
+				Extensions map[string]interface{} `json:"extensions"`
+			} `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+		require.Len(t, resp.Errors, 1, rec.Body.String())
+
+		return resp.Errors[0].Extensions
+	}
+
+	extensions := do(`mutation { updateTodoItem(input: {id: "unknown", completed: true}) { id } }`)
+	assert.Equal(t, "NOT_FOUND", extensions["code"])
+
+	extensions = do(`mutation { addTodoItem(input: {title: ""}) { id } }`)
+	assert.Equal(t, "INVALID_ARGUMENT", extensions["code"])
+	assert.Equal(t, map[string]interface{}{"title": []interface{}{"title cannot be empty"}}, extensions["violations"])
+
+	// Errors of the query itself keep the code set by gqlgen
+	extensions = do(`query { unknownField }`)
+	assert.Equal(t, "GRAPHQL_VALIDATION_FAILED", extensions["code"])
+}
+
+func TestGraphQLErrorCode(t *testing.T) {
+	assert.Equal(t, "OK", graphQLErrorCode(codes.OK))
+	assert.Equal(t, "NOT_FOUND", graphQLErrorCode(codes.NotFound))
+	assert.Equal(t, "DEADLINE_EXCEEDED", graphQLErrorCode(codes.DeadlineExceeded))
+	assert.Equal(t, "UNAUTHENTICATED", graphQLErrorCode(codes.Unauthenticated))
+}
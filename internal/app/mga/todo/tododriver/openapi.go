@@ -0,0 +1,105 @@
+package tododriver
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+
+	"emperror.dev/errors"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// problemContentType is the content type of errors returned by the REST API.
+const problemContentType = "application/problem+json"
+
+// OpenAPIHandler serves an OpenAPI document from a filesystem.
+func OpenAPIHandler(fsys fs.FS, name string) http.Handler {
+	body, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		panic(err)
+	}
+
+	return openAPIHandler(body)
+}
+
+// OpenAPIDocumentHandler serves an OpenAPI 3 document as JSON.
+func OpenAPIDocumentHandler(doc *openapi3.T) http.Handler {
+	body, err := json.Marshal(doc)
+	if err != nil {
+		panic(err)
+	}
+
+	return openAPIHandler(body)
+}
+
+func openAPIHandler(body []byte) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		_, _ = w.Write(body)
+	})
+}
+
+// AdaptOpenAPIDocument adapts the OpenAPI document of the todo API (as published upstream) to the REST API:
+//
+//   - the API is served by the application itself (instead of the example servers of the document)
+//   - items can be added without an order (it defaults to 0, like in the TodoMVC backend project)
+//   - errors are returned as problem details (RFC 7807) including validation violations
+//   - requests not matching the document are rejected with 400 Bad Request (see openapivalidator)
+func AdaptOpenAPIDocument(doc *openapi3.T) error {
+	doc.Servers = openapi3.Servers{{URL: "/"}}
+
+	if schema := doc.Components.Schemas["AddTodoItemRequest"]; schema != nil {
+		required := schema.Value.Required[:0]
+
+		for _, property := range schema.Value.Required {
+			if property != "order" {
+				required = append(required, property)
+			}
+		}
+
+		schema.Value.Required = required
+	}
+
+	if schema := doc.Components.Schemas["Error"]; schema != nil {
+		schema.Value.Description = "RFC-7807 problem details"
+		violations := openapi3.NewObjectSchema().
+			WithAdditionalProperties(openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema()))
+		violations.Description = "Validation violations by field"
+
+		schema.Value.Properties["violations"] = openapi3.NewSchemaRef("", violations)
+	}
+
+	for _, response := range doc.Components.Responses {
+		content := response.Value.Content
+		if mediaType, ok := content["application/json"]; ok {
+			delete(content, "application/json")
+			content[problemContentType] = mediaType
+		}
+	}
+
+	invalidRequest := doc.Components.Responses["InvalidRequest"]
+	if invalidRequest == nil {
+		return errors.New("OpenAPI document has no InvalidRequest response")
+	}
+
+	invalidRequest.Value.WithDescription("The request does not match the API specification")
+
+	for _, path := range doc.Paths {
+		for _, operation := range path.Operations() {
+			if operation.RequestBody == nil {
+				continue
+			}
+
+			if _, ok := operation.Responses["400"]; !ok {
+				operation.Responses["400"] = &openapi3.ResponseRef{
+					Ref:   "#/components/responses/InvalidRequest",
+					Value: invalidRequest.Value,
+				}
+			}
+		}
+	}
+
+	return errors.WrapIf(doc.Validate(context.Background()), "invalid OpenAPI document")
+}
@@ -0,0 +1,64 @@
+package tododriver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sagikazarmark/modern-go-application/api/openapi"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/openapivalidator"
+)
+
+func TestAdaptOpenAPIDocument(t *testing.T) {
+	doc, err := openapivalidator.Load(openapi.Files(), "todo.yaml")
+	require.NoError(t, err)
+
+	require.NoError(t, AdaptOpenAPIDocument(doc))
+
+	require.Len(t, doc.Servers, 1)
+	assert.Equal(t, "/", doc.Servers[0].URL)
+
+	assert.Equal(t, []string{"title"}, doc.Components.Schemas["AddTodoItemRequest"].Value.Required)
+	assert.Contains(t, doc.Components.Schemas["Error"].Value.Properties, "violations")
+	assert.Contains(t, doc.Components.Responses["NotFound"].Value.Content, problemContentType)
+
+	// Operations with a request body are validated
+	assert.Contains(t, doc.Paths["/todos/{id}"].Patch.Responses, "400")
+
+	validator, err := openapivalidator.Middleware(doc, func(_ context.Context, err error, w http.ResponseWriter) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	})
+	require.NoError(t, err)
+
+	handler := validator(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	tests := map[string]struct {
+		body string
+		code int
+	}{
+		"withoutOrder": {`{"title":"Buy milk"}`, http.StatusCreated},
+		"withOrder":    {`{"title":"Buy milk","order":1}`, http.StatusCreated},
+		"invalidTitle": {`{"title":1}`, http.StatusBadRequest},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/todos", strings.NewReader(test.body))
+			req.Header.Set("Content-Type", "application/json")
+
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			assert.Equal(t, test.code, rec.Code, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package tododriver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	todov1gw "github.com/sagikazarmark/modern-go-application/api/todo/v1"
+)
+
+// correlationIDHeader is forwarded from HTTP requests to the gRPC service as metadata.
+const correlationIDHeader = "Correlation-Id"
+
+// NewGatewayServeMux returns a new JSON/REST gateway mux.
+func NewGatewayServeMux() *runtime.ServeMux {
+	return runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			if http.CanonicalHeaderKey(key) == correlationIDHeader {
+				return strings.ToLower(key), true
+			}
+
+			return runtime.DefaultHeaderMatcher(key)
+		}),
+	)
+}
+
+// RegisterGatewayHandlers registers a JSON/REST gateway to a TodoListService server under /api/v1.
+//
+// Requests are passed to the server in-process (just like gRPC requests), so both APIs behave identically:
+// gRPC status codes are mapped to HTTP status codes the same way gRPC-Gateway does.
+// The gateway is generated from the HTTP rules in api/todo/v1/todo_list_http.yaml (see the generate Makefile target).
+func RegisterGatewayHandlers(mux *runtime.ServeMux, server todov1.TodoListServiceServer) error {
+	return errors.WrapIf(
+		todov1gw.RegisterTodoListServiceHandlerServer(context.Background(), mux, server),
+		"failed to register gateway handlers",
+	)
+}
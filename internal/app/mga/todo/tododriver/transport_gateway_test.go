@@ -0,0 +1,68 @@
+package tododriver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/goph/idgen/ulidgen"
+	"github.com/sagikazarmark/todobackend-go-kit/todo"
+	kittododriver "github.com/sagikazarmark/todobackend-go-kit/todo/tododriver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRegisterGatewayHandlers(t *testing.T) {
+	service := todo.NewService(ulidgen.NewGenerator(), todo.NewInMemoryStore())
+	server := kittododriver.MakeGRPCServer(kittododriver.MakeEndpoints(service))
+
+	mux := NewGatewayServeMux()
+	require.NoError(t, RegisterGatewayHandlers(mux, server))
+
+	do := func(method string, path string, body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+
+		mux.ServeHTTP(rec, req)
+
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp), rec.Body.String())
+
+		return rec.Code, resp
+	}
+
+	code, resp := do(http.MethodPost, "/api/v1/items", `{"title":"Buy milk","order":1}`)
+	require.Equal(t, http.StatusOK, code)
+
+	item := resp["item"].(map[string]interface{})
+	id := item["id"].(string)
+
+	assert.Equal(t, "Buy milk", item["title"])
+	assert.Equal(t, false, item["completed"])
+
+	code, resp = do(http.MethodPatch, "/api/v1/items/"+id, `{"completed":true}`)
+	require.Equal(t, http.StatusOK, code)
+
+	item = resp["item"].(map[string]interface{})
+	assert.Equal(t, "Buy milk", item["title"])
+	assert.Equal(t, true, item["completed"])
+
+	code, resp = do(http.MethodGet, "/api/v1/items", "")
+	require.Equal(t, http.StatusOK, code)
+	assert.Len(t, resp["items"], 1)
+
+	code, _ = do(http.MethodDelete, "/api/v1/items/"+id, "")
+	require.Equal(t, http.StatusOK, code)
+
+	// Errors are mapped from gRPC status codes
+	code, resp = do(http.MethodGet, "/api/v1/items/"+id, "")
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Equal(t, float64(codes.NotFound), resp["code"])
+
+	code, resp = do(http.MethodPost, "/api/v1/items", `{"title":`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, float64(codes.InvalidArgument), resp["code"])
+}
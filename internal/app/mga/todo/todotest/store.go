@@ -0,0 +1,167 @@
+// Package todotest provides utilities for testing todo components.
+package todotest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"emperror.dev/errors"
+	"github.com/sagikazarmark/todobackend-go-kit/todo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RunStoreTests runs a conformance test suite against a todo.Store implementation.
+// newStore is called for every test case and must return an empty store.
+func RunStoreTests(t *testing.T, newStore func(t *testing.T) todo.Store) {
+	t.Run("NotFound", func(t *testing.T) {
+		store := newStore(t)
+
+		_, err := store.GetOne(context.Background(), "unknown")
+		require.Error(t, err)
+
+		var notFoundErr todo.NotFoundError
+
+		require.True(t, errors.As(err, &notFoundErr), "error is not a todo.NotFoundError: %v", err)
+		assert.Equal(t, "unknown", notFoundErr.ID)
+
+		// Deleting a missing item is not an error
+		assert.NoError(t, store.DeleteOne(context.Background(), "unknown"))
+	})
+
+	t.Run("StoreAndGet", func(t *testing.T) {
+		store := newStore(t)
+		item := todo.Item{ID: "item-1", Title: "Buy milk", Completed: true, Order: 3}
+
+		require.NoError(t, store.Store(context.Background(), item))
+
+		storedItem, err := store.GetOne(context.Background(), item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, item, storedItem)
+	})
+
+	t.Run("Upsert", func(t *testing.T) {
+		store := newStore(t)
+
+		require.NoError(t, store.Store(context.Background(), todo.Item{ID: "item-1", Title: "Buy milk", Order: 1}))
+
+		item := todo.Item{ID: "item-1", Title: "Buy oat milk", Completed: true, Order: 2}
+		require.NoError(t, store.Store(context.Background(), item))
+
+		storedItem, err := store.GetOne(context.Background(), item.ID)
+		require.NoError(t, err)
+		assert.Equal(t, item, storedItem)
+
+		items, err := store.GetAll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []todo.Item{item}, items)
+	})
+
+	t.Run("Ordering", func(t *testing.T) {
+		store := newStore(t)
+
+		items, err := store.GetAll(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, items)
+
+		// Items are returned ordered by ID regardless of the order they are stored in
+		for _, id := range []string{"item-c", "item-a", "item-b"} {
+			require.NoError(t, store.Store(context.Background(), todo.Item{ID: id, Title: id}))
+		}
+
+		items, err = store.GetAll(context.Background())
+		require.NoError(t, err)
+		expected := []todo.Item{
+			{ID: "item-a", Title: "item-a"},
+			{ID: "item-b", Title: "item-b"},
+			{ID: "item-c", Title: "item-c"},
+		}
+
+		assert.Equal(t, expected, items)
+	})
+
+	t.Run("DeleteOne", func(t *testing.T) {
+		store := newStore(t)
+
+		require.NoError(t, store.Store(context.Background(), todo.Item{ID: "item-1", Title: "Buy milk"}))
+		require.NoError(t, store.Store(context.Background(), todo.Item{ID: "item-2", Title: "Call mom"}))
+
+		require.NoError(t, store.DeleteOne(context.Background(), "item-1"))
+
+		_, err := store.GetOne(context.Background(), "item-1")
+		assert.True(t, errors.As(err, &todo.NotFoundError{}), "error is not a todo.NotFoundError: %v", err)
+
+		items, err := store.GetAll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []todo.Item{{ID: "item-2", Title: "Call mom"}}, items)
+	})
+
+	t.Run("DeleteAll", func(t *testing.T) {
+		store := newStore(t)
+
+		require.NoError(t, store.Store(context.Background(), todo.Item{ID: "item-1", Title: "Buy milk"}))
+		require.NoError(t, store.Store(context.Background(), todo.Item{ID: "item-2", Title: "Call mom"}))
+
+		require.NoError(t, store.DeleteAll(context.Background()))
+
+		items, err := store.GetAll(context.Background())
+		require.NoError(t, err)
+		assert.Empty(t, items)
+
+		// The store can be used after deleting every item
+		require.NoError(t, store.Store(context.Background(), todo.Item{ID: "item-1", Title: "Buy milk"}))
+
+		items, err = store.GetAll(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []todo.Item{{ID: "item-1", Title: "Buy milk"}}, items)
+	})
+
+	t.Run("ConcurrentWrites", func(t *testing.T) {
+		store := newStore(t)
+
+		const writers = 10
+
+		var wg sync.WaitGroup
+
+		errs := make(chan error, writers*3)
+
+		for i := 0; i < writers; i++ {
+			i := i
+
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				ctx := context.Background()
+				id := fmt.Sprintf("item-%02d", i)
+
+				errs <- store.Store(ctx, todo.Item{ID: id, Title: id})
+				errs <- store.Store(ctx, todo.Item{ID: id, Title: id, Completed: true, Order: i})
+
+				// Every writer creates or updates the same item
+				errs <- store.Store(ctx, todo.Item{ID: "shared", Title: fmt.Sprintf("writer %d", i)})
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+
+		items, err := store.GetAll(context.Background())
+		require.NoError(t, err)
+		require.Len(t, items, writers+1)
+
+		for i, item := range items[:writers] {
+			assert.Equal(t, todo.Item{ID: fmt.Sprintf("item-%02d", i), Title: item.ID, Completed: true, Order: i}, item)
+		}
+
+		assert.Equal(t, "shared", items[writers].ID)
+		assert.Regexp(t, `^writer \d$`, items[writers].Title)
+	})
+}
@@ -0,0 +1,13 @@
+package todotest
+
+import (
+	"testing"
+
+	"github.com/sagikazarmark/todobackend-go-kit/todo"
+)
+
+func TestInMemoryStore(t *testing.T) {
+	RunStoreTests(t, func(_ *testing.T) todo.Store {
+		return todo.NewInMemoryStore()
+	})
+}
@@ -6,15 +6,17 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
-	"google.golang.org/genproto/googleapis/rpc/errdetails"
-	"google.golang.org/grpc/status"
 
 	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
 )
 
 type createOptions struct {
-	title  string
-	client todov1.TodoListServiceClient
+	title   string
+	client  todov1.TodoListServiceClient
+	printer output.Printer
+	timeout time.Duration
 }
 
 // NewAddCommand creates a new cobra.Command for adding a new item to the list.
@@ -29,6 +31,8 @@ func NewAddCommand(c Context) *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.title = args[0]
 			options.client = c.GetTodoClient()
+			options.printer = c.GetPrinter()
+			options.timeout = c.GetTimeout()
 
 			cmd.SilenceErrors = true
 			cmd.SilenceUsage = true
@@ -45,28 +49,16 @@ func runCreate(options createOptions) error {
 		Title: options.title,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), options.timeout)
 	defer cancel()
 
 	resp, err := options.client.AddItem(ctx, req)
 	if err != nil {
-		st := status.Convert(err)
-		for _, detail := range st.Details() {
-			// nolint: gocritic
-			switch t := detail.(type) {
-			case *errdetails.BadRequest:
-				fmt.Println("Oops! Your request was rejected by the server.")
-				for _, violation := range t.GetFieldViolations() {
-					fmt.Printf("The %q field was wrong:\n", violation.GetField())
-					fmt.Printf("\t%s\n", violation.GetDescription())
-				}
-			}
-		}
-
 		return err
 	}
 
-	fmt.Printf("Todo item %q with ID %s has been created.", options.title, resp.GetItem().GetId())
-
-	return nil
+	return options.printer.Print(changedTodoItem{
+		todoItem: newTodoItem(resp.GetItem()),
+		message:  fmt.Sprintf("Todo item %q with ID %s has been created.", options.title, resp.GetItem().GetId()),
+	})
 }
@@ -0,0 +1,65 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
+)
+
+type clearOptions struct {
+	yes     bool
+	client  todov1.TodoListServiceClient
+	printer output.Printer
+	timeout time.Duration
+}
+
+// NewClearCommand creates a new cobra.Command for deleting all todo items.
+func NewClearCommand(c Context) *cobra.Command {
+	options := clearOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Delete all todo items",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			options.client = c.GetTodoClient()
+			options.printer = c.GetPrinter()
+			options.timeout = c.GetTimeout()
+
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			if !options.yes && !confirm(cmd.InOrStdin(), cmd.ErrOrStderr(), "Delete all todo items?") {
+				fmt.Fprintln(cmd.ErrOrStderr(), "Todo items have not been deleted.")
+
+				return nil
+			}
+
+			return runClear(options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.yes, "yes", "y", false, "Delete without asking for confirmation")
+
+	return cmd
+}
+
+func runClear(options clearOptions) error {
+	req := &todov1.DeleteItemsRequest{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), options.timeout)
+	defer cancel()
+
+	_, err := options.client.DeleteItems(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return options.printer.Print(deletedTodoItems{})
+}
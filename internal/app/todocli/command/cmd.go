@@ -1,14 +1,25 @@
 package command
 
 import (
+	"time"
+
 	"github.com/spf13/cobra"
 
 	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/config"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/offline"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
 )
 
 // Context represents the application context.
 type Context interface {
 	GetTodoClient() todov1.TodoListServiceClient
+	GetOfflineClient() *offline.Client
+	GetPrinter() output.Printer
+	GetTimeout() time.Duration
+	GetConfigPath() string
+	NewContextFromFlags() (config.Context, error)
 }
 
 // AddCommands adds all the commands from cli/command to the root command.
@@ -16,6 +27,15 @@ func AddCommands(cmd *cobra.Command, c Context) {
 	cmd.AddCommand(
 		NewAddCommand(c),
 		NewListCommand(c),
+		NewGetCommand(c),
+		NewEditCommand(c),
 		NewMarkAsCompleteCommand(c),
+		NewReopenCommand(c),
+		NewDeleteCommand(c),
+		NewClearCommand(c),
+		NewSyncCommand(c),
+		NewTUICommand(c),
+		NewContextCommand(c),
+		NewCompletionCommand(),
 	)
 }
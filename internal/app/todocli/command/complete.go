@@ -9,11 +9,15 @@ import (
 	"github.com/spf13/cobra"
 
 	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
 )
 
 type markAsCompleteOptions struct {
-	todoID string
-	client todov1.TodoListServiceClient
+	todoID  string
+	client  todov1.TodoListServiceClient
+	printer output.Printer
+	timeout time.Duration
 }
 
 // NewMarkAsCompleteCommand creates a new cobra.Command for marking a todo item as complete.
@@ -21,13 +25,17 @@ func NewMarkAsCompleteCommand(c Context) *cobra.Command {
 	options := markAsCompleteOptions{}
 
 	cmd := &cobra.Command{
-		Use:     "complete",
-		Aliases: []string{"c"},
-		Short:   "Mark a todo item as complete",
-		Args:    cobra.ExactArgs(1),
+		Use:               "complete ITEM",
+		Aliases:           []string{"c"},
+		Short:             "Mark a todo item as complete",
+		Long:              "Mark a todo item as complete.\n\n" + itemRefHelp,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeItems(c),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			options.todoID = args[0]
 			options.client = c.GetTodoClient()
+			options.printer = c.GetPrinter()
+			options.timeout = c.GetTimeout()
 
 			cmd.SilenceErrors = true
 			cmd.SilenceUsage = true
@@ -40,22 +48,28 @@ func NewMarkAsCompleteCommand(c Context) *cobra.Command {
 }
 
 func runMarkAsComplete(options markAsCompleteOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), options.timeout)
+	defer cancel()
+
+	item, err := resolveItem(ctx, options.client, options.todoID)
+	if err != nil {
+		return err
+	}
+
 	req := &todov1.UpdateItemRequest{
-		Id: options.todoID,
+		Id: item.GetId(),
 		Completed: &wrappers.BoolValue{
 			Value: true,
 		},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-	defer cancel()
-
-	_, err := options.client.UpdateItem(ctx, req)
+	resp, err := options.client.UpdateItem(ctx, req)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Todo item with ID %s has been marked as complete.", options.todoID)
-
-	return nil
+	return options.printer.Print(changedTodoItem{
+		todoItem: newTodoItem(resp.GetItem()),
+		message:  fmt.Sprintf("Todo item with ID %s has been marked as complete.", item.GetId()),
+	})
 }
@@ -0,0 +1,47 @@
+package command
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCompletionCommand creates a new cobra.Command for generating shell completion scripts.
+func NewCompletionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion [bash|zsh|fish|powershell]",
+		Short: "Generate a shell completion script",
+		Long: `Generate a shell completion script.
+
+Commands taking a todo item complete the IDs and titles of items (fetched from the service).
+
+To load completions in the current bash session:
+
+  source <(todocli completion bash)
+
+To load completions for every zsh session:
+
+  todocli completion zsh > "${fpath[1]}/_todocli"`,
+		Args:                  cobra.ExactValidArgs(1),
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			root := cmd.Root()
+
+			switch args[0] {
+			case "bash":
+				return root.GenBashCompletion(out)
+
+			case "zsh":
+				return root.GenZshCompletion(out)
+
+			case "fish":
+				return root.GenFishCompletion(out, true)
+
+			default:
+				return root.GenPowerShellCompletionWithDesc(out)
+			}
+		},
+	}
+
+	return cmd
+}
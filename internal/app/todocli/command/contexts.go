@@ -0,0 +1,241 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/config"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
+)
+
+// NewContextCommand creates a new cobra.Command for managing contexts.
+func NewContextCommand(c Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "context",
+		Aliases: []string{"ctx"},
+		Short:   "Manage contexts (todo services to connect to)",
+	}
+
+	cmd.AddCommand(
+		NewContextListCommand(c),
+		NewContextUseCommand(c),
+		NewContextAddCommand(c),
+	)
+
+	return cmd
+}
+
+type contextListOptions struct {
+	configPath string
+	printer    output.Printer
+}
+
+// NewContextListCommand creates a new cobra.Command for listing contexts.
+func NewContextListCommand(c Context) *cobra.Command {
+	options := contextListOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"l", "ls"},
+		Short:   "List contexts",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			options.configPath = c.GetConfigPath()
+			options.printer = c.GetPrinter()
+
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runContextList(options)
+		},
+	}
+
+	return cmd
+}
+
+func runContextList(options contextListOptions) error {
+	cfg, err := config.Load(options.configPath)
+	if err != nil {
+		return err
+	}
+
+	list := make(contextList, 0, len(cfg.Contexts))
+
+	for _, name := range cfg.ContextNames() {
+		transport := cfg.Contexts[name].Transport
+		if transport == "" {
+			transport = config.TransportGRPC
+		}
+
+		list = append(list, contextInfo{
+			Name:      name,
+			Address:   cfg.Contexts[name].Address,
+			Transport: transport,
+			Current:   name == cfg.CurrentContext,
+		})
+	}
+
+	return options.printer.Print(list)
+}
+
+type contextUseOptions struct {
+	name       string
+	configPath string
+	printer    output.Printer
+}
+
+// NewContextUseCommand creates a new cobra.Command for switching the current context.
+func NewContextUseCommand(c Context) *cobra.Command {
+	options := contextUseOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "use",
+		Short: "Switch the current context",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.name = args[0]
+			options.configPath = c.GetConfigPath()
+			options.printer = c.GetPrinter()
+
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runContextUse(options)
+		},
+	}
+
+	return cmd
+}
+
+func runContextUse(options contextUseOptions) error {
+	cfg, err := config.Load(options.configPath)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := cfg.Contexts[options.name]; !ok {
+		return errors.Errorf("context %q does not exist", options.name)
+	}
+
+	cfg.CurrentContext = options.name
+
+	if err := cfg.Save(options.configPath); err != nil {
+		return err
+	}
+
+	return options.printer.Print(changedContext{
+		Name:    options.name,
+		message: fmt.Sprintf("Switched to context %q.", options.name),
+	})
+}
+
+type contextAddOptions struct {
+	name       string
+	context    config.Context
+	use        bool
+	configPath string
+	printer    output.Printer
+}
+
+// NewContextAddCommand creates a new cobra.Command for adding (or replacing) a context.
+// The settings of the context are taken from the global flags (eg. --address).
+func NewContextAddCommand(c Context) *cobra.Command {
+	options := contextAddOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Add a context with the settings given as flags (replacing any existing one with the same name)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			todoContext, err := c.NewContextFromFlags()
+			if err != nil {
+				return err
+			}
+
+			options.name = args[0]
+			options.context = todoContext
+			options.configPath = c.GetConfigPath()
+			options.printer = c.GetPrinter()
+
+			return runContextAdd(options)
+		},
+	}
+
+	cmd.Flags().BoolVar(&options.use, "use", false, "Switch to the context")
+
+	return cmd
+}
+
+func runContextAdd(options contextAddOptions) error {
+	cfg, err := config.Load(options.configPath)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Contexts == nil {
+		cfg.Contexts = make(map[string]config.Context)
+	}
+
+	cfg.Contexts[options.name] = options.context
+
+	// The first context becomes the current one
+	if options.use || cfg.CurrentContext == "" {
+		cfg.CurrentContext = options.name
+	}
+
+	if err := cfg.Save(options.configPath); err != nil {
+		return err
+	}
+
+	return options.printer.Print(changedContext{
+		Name:    options.name,
+		message: fmt.Sprintf("Context %q has been added.", options.name),
+	})
+}
+
+// contextInfo is a context returned by a command.
+type contextInfo struct {
+	Name      string `json:"name"`
+	Address   string `json:"address"`
+	Transport string `json:"transport"`
+	Current   bool   `json:"current"`
+}
+
+// contextList is a list of contexts returned by a command.
+type contextList []contextInfo
+
+func (l contextList) Header() []string {
+	return []string{"Name", "Address", "Transport", "Current"}
+}
+
+func (l contextList) Rows() [][]string {
+	rows := make([][]string, 0, len(l))
+
+	for _, context := range l {
+		rows = append(rows, []string{
+			context.Name,
+			context.Address,
+			context.Transport,
+			strconv.FormatBool(context.Current),
+		})
+	}
+
+	return rows
+}
+
+// changedContext is a context changed by a command.
+type changedContext struct {
+	Name string `json:"name"`
+
+	message string
+}
+
+func (c changedContext) String() string {
+	return c.message
+}
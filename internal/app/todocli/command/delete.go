@@ -0,0 +1,95 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
+)
+
+type deleteOptions struct {
+	todoID  string
+	confirm func(question string) bool
+	client  todov1.TodoListServiceClient
+	printer output.Printer
+	timeout time.Duration
+}
+
+// NewDeleteCommand creates a new cobra.Command for deleting a todo item.
+func NewDeleteCommand(c Context) *cobra.Command {
+	options := deleteOptions{}
+
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:               "delete ITEM",
+		Aliases:           []string{"d", "rm"},
+		Short:             "Delete a todo item",
+		Long:              "Delete a todo item.\n\n" + itemRefHelp,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeItems(c),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.todoID = args[0]
+			options.client = c.GetTodoClient()
+			options.printer = c.GetPrinter()
+			options.timeout = c.GetTimeout()
+
+			options.confirm = func(question string) bool {
+				if yes {
+					return true
+				}
+
+				if !confirm(cmd.InOrStdin(), cmd.ErrOrStderr(), question) {
+					fmt.Fprintln(cmd.ErrOrStderr(), "Todo item has not been deleted.")
+
+					return false
+				}
+
+				return true
+			}
+
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runDelete(options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Delete without asking for confirmation")
+
+	return cmd
+}
+
+func runDelete(options deleteOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), options.timeout)
+	defer cancel()
+
+	item, err := resolveItem(ctx, options.client, options.todoID)
+	if err != nil {
+		return err
+	}
+
+	if !options.confirm(fmt.Sprintf("Delete todo item %q with ID %s?", item.GetTitle(), item.GetId())) {
+		return nil
+	}
+
+	req := &todov1.DeleteItemRequest{
+		Id: item.GetId(),
+	}
+
+	// The confirmation is not part of the request timeout
+	ctx, cancel = context.WithTimeout(context.Background(), options.timeout)
+	defer cancel()
+
+	_, err = options.client.DeleteItem(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return options.printer.Print(deletedTodoItem{Id: item.GetId()})
+}
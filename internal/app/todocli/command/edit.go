@@ -0,0 +1,108 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/spf13/cobra"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
+)
+
+type editOptions struct {
+	todoID  string
+	title   *string
+	order   *int32
+	client  todov1.TodoListServiceClient
+	printer output.Printer
+	timeout time.Duration
+}
+
+// NewEditCommand creates a new cobra.Command for editing a todo item.
+func NewEditCommand(c Context) *cobra.Command {
+	options := editOptions{}
+
+	var title string
+	var order int32
+
+	cmd := &cobra.Command{
+		Use:               "edit ITEM",
+		Aliases:           []string{"e"},
+		Short:             "Edit a todo item",
+		Long:              "Edit a todo item.\n\n" + itemRefHelp,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeItems(c),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.todoID = args[0]
+			options.client = c.GetTodoClient()
+			options.printer = c.GetPrinter()
+			options.timeout = c.GetTimeout()
+
+			// Only send the fields that were set explicitly
+			if cmd.Flags().Changed("title") {
+				options.title = &title
+			}
+
+			if cmd.Flags().Changed("order") {
+				options.order = &order
+			}
+
+			if options.title == nil && options.order == nil {
+				return errors.New("nothing to edit: set --title and/or --order")
+			}
+
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runEdit(options)
+		},
+	}
+
+	flags := cmd.Flags()
+
+	flags.StringVar(&title, "title", "", "New title of the todo item")
+	flags.Int32Var(&order, "order", 0, "New order of the todo item")
+
+	return cmd
+}
+
+func runEdit(options editOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), options.timeout)
+	defer cancel()
+
+	item, err := resolveItem(ctx, options.client, options.todoID)
+	if err != nil {
+		return err
+	}
+
+	req := &todov1.UpdateItemRequest{
+		Id: item.GetId(),
+	}
+
+	if options.title != nil {
+		req.Title = &wrappers.StringValue{
+			Value: *options.title,
+		}
+	}
+
+	if options.order != nil {
+		req.Order = &wrappers.Int32Value{
+			Value: *options.order,
+		}
+	}
+
+	resp, err := options.client.UpdateItem(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return options.printer.Print(changedTodoItem{
+		todoItem: newTodoItem(resp.GetItem()),
+		message:  fmt.Sprintf("Todo item with ID %s has been updated.", item.GetId()),
+	})
+}
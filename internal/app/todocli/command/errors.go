@@ -0,0 +1,83 @@
+package command
+
+import (
+	"fmt"
+	"io"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Exit codes returned by the CLI.
+const (
+	ExitOK              = 0
+	ExitError           = 1
+	ExitInvalidArgument = 3
+	ExitNotFound        = 4
+	ExitConflict        = 5
+	ExitPermission      = 6
+	ExitUnavailable     = 7
+)
+
+// ExitCode returns the exit code for an error returned by a command.
+// Errors returned by the service are mapped from their gRPC status code.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return ExitError
+	}
+
+	switch st.Code() {
+	case codes.OK:
+		return ExitOK
+
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return ExitInvalidArgument
+
+	case codes.NotFound:
+		return ExitNotFound
+
+	case codes.AlreadyExists, codes.Aborted:
+		return ExitConflict
+
+	case codes.PermissionDenied, codes.Unauthenticated:
+		return ExitPermission
+
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Canceled:
+		return ExitUnavailable
+
+	default:
+		return ExitError
+	}
+}
+
+// PrintError renders an error returned by a command.
+// Field violations of rejected requests are listed one by one.
+func PrintError(w io.Writer, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		fmt.Fprintf(w, "Error: %s\n", err)
+
+		return
+	}
+
+	fmt.Fprintf(w, "Error: %s\n", st.Message())
+
+	for _, detail := range st.Details() {
+		// nolint: gocritic
+		switch t := detail.(type) {
+		case *errdetails.BadRequest:
+			fmt.Fprintln(w, "Oops! Your request was rejected by the server.")
+
+			for _, violation := range t.GetFieldViolations() {
+				fmt.Fprintf(w, "The %q field was wrong:\n", violation.GetField())
+				fmt.Fprintf(w, "\t%s\n", violation.GetDescription())
+			}
+		}
+	}
+}
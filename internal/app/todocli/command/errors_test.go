@@ -0,0 +1,64 @@
+package command
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected int
+	}{
+		"nil":             {nil, ExitOK},
+		"error":           {errors.New("error"), ExitError},
+		"internal":        {status.Error(codes.Internal, "internal"), ExitError},
+		"invalidArgument": {status.Error(codes.InvalidArgument, "invalid"), ExitInvalidArgument},
+		"notFound":        {status.Error(codes.NotFound, "not found"), ExitNotFound},
+		"alreadyExists":   {status.Error(codes.AlreadyExists, "exists"), ExitConflict},
+		"unauthenticated": {status.Error(codes.Unauthenticated, "unauthenticated"), ExitPermission},
+		"unavailable":     {status.Error(codes.Unavailable, "unavailable"), ExitUnavailable},
+		"rateLimited":     {status.Error(codes.ResourceExhausted, "rate limit exceeded"), ExitUnavailable},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, ExitCode(test.err))
+		})
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "invalid todo item").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "title", Description: "cannot be empty"},
+		},
+	})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	PrintError(&buf, st.Err())
+
+	expected := "Error: invalid todo item\n" +
+		"Oops! Your request was rejected by the server.\n" +
+		"The \"title\" field was wrong:\n" +
+		"\tcannot be empty\n"
+
+	assert.Equal(t, expected, buf.String())
+
+	buf.Reset()
+
+	PrintError(&buf, errors.New("failed to dial service"))
+
+	assert.Equal(t, "Error: failed to dial service\n", buf.String())
+}
@@ -0,0 +1,58 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
+)
+
+type getOptions struct {
+	todoID  string
+	client  todov1.TodoListServiceClient
+	printer output.Printer
+	timeout time.Duration
+}
+
+// NewGetCommand creates a new cobra.Command for getting a todo item.
+func NewGetCommand(c Context) *cobra.Command {
+	options := getOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "get ITEM",
+		Aliases:           []string{"g"},
+		Short:             "Get a todo item",
+		Long:              "Get a todo item.\n\n" + itemRefHelp,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeItems(c),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.todoID = args[0]
+			options.client = c.GetTodoClient()
+			options.printer = c.GetPrinter()
+			options.timeout = c.GetTimeout()
+
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runGet(options)
+		},
+	}
+
+	return cmd
+}
+
+func runGet(options getOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), options.timeout)
+	defer cancel()
+
+	item, err := resolveItem(ctx, options.client, options.todoID)
+	if err != nil {
+		return err
+	}
+
+	return options.printer.Print(newTodoItem(item))
+}
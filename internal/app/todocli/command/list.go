@@ -2,18 +2,19 @@ package command
 
 import (
 	"context"
-	"os"
-	"strconv"
 	"time"
 
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 
 	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
 )
 
 type listOptions struct {
-	client todov1.TodoListServiceClient
+	client  todov1.TodoListServiceClient
+	printer output.Printer
+	timeout time.Duration
 }
 
 // NewListCommand creates a new cobra.Command for listing todo items.
@@ -27,6 +28,8 @@ func NewListCommand(c Context) *cobra.Command {
 		Args:    cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
 			options.client = c.GetTodoClient()
+			options.printer = c.GetPrinter()
+			options.timeout = c.GetTimeout()
 
 			cmd.SilenceErrors = true
 			cmd.SilenceUsage = true
@@ -42,7 +45,7 @@ func NewListCommand(c Context) *cobra.Command {
 func runList(options listOptions) error {
 	req := &todov1.ListItemsRequest{}
 
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), options.timeout)
 	defer cancel()
 
 	resp, err := options.client.ListItems(ctx, req)
@@ -50,13 +53,5 @@ func runList(options listOptions) error {
 		return err
 	}
 
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"ID", "Title", "Completed"})
-
-	for _, item := range resp.GetItems() {
-		table.Append([]string{item.GetId(), item.GetTitle(), strconv.FormatBool(item.GetCompleted())})
-	}
-	table.Render()
-
-	return nil
+	return options.printer.Print(newTodoItemList(resp.GetItems()))
 }
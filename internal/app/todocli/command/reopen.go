@@ -0,0 +1,74 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/spf13/cobra"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
+)
+
+type reopenOptions struct {
+	todoID  string
+	client  todov1.TodoListServiceClient
+	printer output.Printer
+	timeout time.Duration
+}
+
+// NewReopenCommand creates a new cobra.Command for marking a todo item as incomplete.
+func NewReopenCommand(c Context) *cobra.Command {
+	options := reopenOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "reopen ITEM",
+		Short:             "Mark a todo item as incomplete",
+		Long:              "Mark a todo item as incomplete.\n\n" + itemRefHelp,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeItems(c),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.todoID = args[0]
+			options.client = c.GetTodoClient()
+			options.printer = c.GetPrinter()
+			options.timeout = c.GetTimeout()
+
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runReopen(options)
+		},
+	}
+
+	return cmd
+}
+
+func runReopen(options reopenOptions) error {
+	ctx, cancel := context.WithTimeout(context.Background(), options.timeout)
+	defer cancel()
+
+	item, err := resolveItem(ctx, options.client, options.todoID)
+	if err != nil {
+		return err
+	}
+
+	req := &todov1.UpdateItemRequest{
+		Id: item.GetId(),
+		Completed: &wrappers.BoolValue{
+			Value: false,
+		},
+	}
+
+	resp, err := options.client.UpdateItem(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	return options.printer.Print(changedTodoItem{
+		todoItem: newTodoItem(resp.GetItem()),
+		message:  fmt.Sprintf("Todo item with ID %s has been reopened.", item.GetId()),
+	})
+}
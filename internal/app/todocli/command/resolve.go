@@ -0,0 +1,139 @@
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+)
+
+// itemRefHelp describes how commands taking a todo item argument find the item.
+const itemRefHelp = "ITEM is the ID of a todo item, a unique prefix of the ID or the title of the item."
+
+// resolveItem finds the todo item referenced by a command argument.
+//
+// An item can be referenced by its ID, a unique (case-insensitive) ID prefix or its title (ignoring case).
+// Titles must match exactly: commands like delete must not act on an item that merely contains the argument.
+func resolveItem(ctx context.Context, client todov1.TodoListServiceClient, ref string) (*todov1.TodoItem, error) {
+	if ref == "" {
+		return nil, status.Error(codes.InvalidArgument, "todo item must not be empty")
+	}
+
+	resp, err := client.ListItems(ctx, &todov1.ListItemsRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	items := resp.GetItems()
+
+	for _, item := range items {
+		if item.GetId() == ref {
+			return item, nil
+		}
+	}
+
+	lowerRef := strings.ToLower(ref)
+
+	matchers := []func(item *todov1.TodoItem) bool{
+		func(item *todov1.TodoItem) bool {
+			return strings.HasPrefix(strings.ToLower(item.GetId()), lowerRef)
+		},
+		func(item *todov1.TodoItem) bool {
+			return strings.ToLower(item.GetTitle()) == lowerRef
+		},
+	}
+
+	for _, match := range matchers {
+		var candidates []*todov1.TodoItem
+
+		for _, item := range items {
+			if match(item) {
+				candidates = append(candidates, item)
+			}
+		}
+
+		switch len(candidates) {
+		case 0:
+			continue
+
+		case 1:
+			return candidates[0], nil
+
+		default:
+			return nil, ambiguousItemError(ref, candidates)
+		}
+	}
+
+	return nil, status.Errorf(codes.NotFound, "no todo item matches %q", ref)
+}
+
+func ambiguousItemError(ref string, candidates []*todov1.TodoItem) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%q matches multiple todo items, use a longer ID prefix:", ref)
+
+	for _, item := range candidates {
+		fmt.Fprintf(&b, "\n  %s  %s", item.GetId(), item.GetTitle())
+	}
+
+	return status.Error(codes.InvalidArgument, b.String())
+}
+
+// completeItems suggests the IDs and titles of todo items for shell completion.
+func completeItems(c Context) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		// The hooks setting up the client run before the flags of the completed command are parsed:
+		// run them again to respect flags like --context
+		if root := cmd.Root(); root.PersistentPreRunE != nil {
+			if err := root.PersistentPreRunE(cmd, args); err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), c.GetTimeout())
+		defer cancel()
+
+		resp, err := c.GetTodoClient().ListItems(ctx, &todov1.ListItemsRequest{})
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		return itemCompletions(resp.GetItems(), toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// itemCompletions returns the IDs of todo items starting with a prefix (ignoring case).
+// When there is no such ID, titles starting with the prefix are returned.
+// The title of an item is shown as the description of its ID and vice versa.
+func itemCompletions(items []*todov1.TodoItem, toComplete string) []string {
+	prefix := strings.ToLower(toComplete)
+
+	var completions []string
+
+	for _, item := range items {
+		if strings.HasPrefix(strings.ToLower(item.GetId()), prefix) {
+			completions = append(completions, item.GetId()+"\t"+item.GetTitle())
+		}
+	}
+
+	if len(completions) > 0 {
+		return completions
+	}
+
+	for _, item := range items {
+		if strings.HasPrefix(strings.ToLower(item.GetTitle()), prefix) {
+			completions = append(completions, item.GetTitle()+"\t"+item.GetId())
+		}
+	}
+
+	return completions
+}
@@ -0,0 +1,149 @@
+package command
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+)
+
+type listItemsClient struct {
+	todov1.TodoListServiceClient
+
+	items []*todov1.TodoItem
+}
+
+func (c listItemsClient) ListItems(
+	_ context.Context,
+	_ *todov1.ListItemsRequest,
+	_ ...grpc.CallOption,
+) (*todov1.ListItemsResponse, error) {
+	return &todov1.ListItemsResponse{Items: c.items}, nil
+}
+
+// nolint: gochecknoglobals
+var testItems = []*todov1.TodoItem{
+	{Id: "01FQ4M2GZ8X1Y7ZK2V3C4B5N6M", Title: "Buy milk"},
+	{Id: "01FQ4M2GZ8X1Y7ZK2V3C4B5N7P", Title: "Buy milk and bread"},
+	{Id: "01FQ4M9A7B6C5D4E3F2G1H0J9K", Title: "Walk the dog"},
+}
+
+func TestResolveItem(t *testing.T) {
+	tests := map[string]struct {
+		ref      string
+		expected string
+	}{
+		"id":            {"01FQ4M2GZ8X1Y7ZK2V3C4B5N6M", "01FQ4M2GZ8X1Y7ZK2V3C4B5N6M"},
+		"idPrefix":      {"01FQ4M9", "01FQ4M9A7B6C5D4E3F2G1H0J9K"},
+		"idPrefixLower": {"01fq4m2gz8x1y7zk2v3c4b5n7", "01FQ4M2GZ8X1Y7ZK2V3C4B5N7P"},
+		"title":         {"buy milk", "01FQ4M2GZ8X1Y7ZK2V3C4B5N6M"},
+		"titleLonger":   {"Buy milk and bread", "01FQ4M2GZ8X1Y7ZK2V3C4B5N7P"},
+	}
+
+	client := listItemsClient{items: testItems}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			item, err := resolveItem(context.Background(), client, test.ref)
+			require.NoError(t, err)
+
+			assert.Equal(t, test.expected, item.GetId())
+		})
+	}
+}
+
+func TestResolveItem_Errors(t *testing.T) {
+	tests := map[string]struct {
+		ref  string
+		code codes.Code
+	}{
+		"empty":             {"", codes.InvalidArgument},
+		"ambiguousIDPrefix": {"01FQ4M", codes.InvalidArgument},
+		"notFound":          {"call mom", codes.NotFound},
+
+		// Parts of titles do not match (destructive commands must not act on an unexpected item)
+		"titleSubstring":       {"dog", codes.NotFound},
+		"titleSubstringUnique": {"milk and", codes.NotFound},
+	}
+
+	client := listItemsClient{items: testItems}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			_, err := resolveItem(context.Background(), client, test.ref)
+			require.Error(t, err)
+
+			assert.Equal(t, test.code, status.Code(err))
+		})
+	}
+}
+
+func TestResolveItem_AmbiguousCandidates(t *testing.T) {
+	_, err := resolveItem(context.Background(), listItemsClient{items: testItems}, "01fq4m2")
+	require.Error(t, err)
+
+	msg := status.Convert(err).Message()
+
+	assert.Contains(t, msg, "01FQ4M2GZ8X1Y7ZK2V3C4B5N6M  Buy milk")
+	assert.Contains(t, msg, "01FQ4M2GZ8X1Y7ZK2V3C4B5N7P  Buy milk and bread")
+	assert.NotContains(t, msg, "Walk the dog")
+}
+
+func TestResolveItem_AmbiguousTitle(t *testing.T) {
+	items := []*todov1.TodoItem{
+		{Id: "01FQ4M2GZ8X1Y7ZK2V3C4B5N6M", Title: "Buy milk"},
+		{Id: "01FQ4M9A7B6C5D4E3F2G1H0J9K", Title: "buy milk"},
+	}
+
+	_, err := resolveItem(context.Background(), listItemsClient{items: items}, "Buy milk")
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestItemCompletions(t *testing.T) {
+	tests := map[string]struct {
+		toComplete string
+		expected   []string
+	}{
+		"all": {
+			"",
+			[]string{
+				"01FQ4M2GZ8X1Y7ZK2V3C4B5N6M\tBuy milk",
+				"01FQ4M2GZ8X1Y7ZK2V3C4B5N7P\tBuy milk and bread",
+				"01FQ4M9A7B6C5D4E3F2G1H0J9K\tWalk the dog",
+			},
+		},
+		"idPrefix": {
+			"01fq4m9",
+			[]string{"01FQ4M9A7B6C5D4E3F2G1H0J9K\tWalk the dog"},
+		},
+		"title": {
+			"buy",
+			[]string{
+				"Buy milk\t01FQ4M2GZ8X1Y7ZK2V3C4B5N6M",
+				"Buy milk and bread\t01FQ4M2GZ8X1Y7ZK2V3C4B5N7P",
+			},
+		},
+		"none": {
+			"call",
+			nil,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, itemCompletions(testItems, test.toComplete))
+		})
+	}
+}
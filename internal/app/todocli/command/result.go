@@ -0,0 +1,193 @@
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/offline"
+)
+
+// todoItem is a todo item returned by a command.
+// Field names match the API, so that templates can refer to them the same way (eg. {{.Id}}).
+// nolint: golint, stylecheck
+type todoItem struct {
+	Id        string `json:"id"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+	Order     int32  `json:"order"`
+}
+
+func newTodoItem(item *todov1.TodoItem) todoItem {
+	return todoItem{
+		Id:        item.GetId(),
+		Title:     item.GetTitle(),
+		Completed: item.GetCompleted(),
+		Order:     item.GetOrder(),
+	}
+}
+
+func (i todoItem) Header() []string {
+	return []string{"ID", "Title", "Completed", "Order"}
+}
+
+func (i todoItem) Rows() [][]string {
+	return [][]string{i.row()}
+}
+
+func (i todoItem) row() []string {
+	return []string{i.Id, i.Title, strconv.FormatBool(i.Completed), strconv.Itoa(int(i.Order))}
+}
+
+// todoItemList is a list of todo items returned by a command.
+type todoItemList []todoItem
+
+func newTodoItemList(items []*todov1.TodoItem) todoItemList {
+	list := make(todoItemList, 0, len(items))
+
+	for _, item := range items {
+		list = append(list, newTodoItem(item))
+	}
+
+	return list
+}
+
+func (l todoItemList) Header() []string {
+	return todoItem{}.Header()
+}
+
+func (l todoItemList) Rows() [][]string {
+	rows := make([][]string, 0, len(l))
+
+	for _, item := range l {
+		rows = append(rows, item.row())
+	}
+
+	return rows
+}
+
+// changedTodoItem is a todo item changed by a command.
+// Humans get a message describing the change instead of the item.
+type changedTodoItem struct {
+	todoItem
+
+	message string
+}
+
+func (i changedTodoItem) String() string {
+	return i.message
+}
+
+// deletedTodoItem is a todo item deleted by a command.
+// nolint: golint, stylecheck
+type deletedTodoItem struct {
+	Id string `json:"id"`
+}
+
+func (i deletedTodoItem) Header() []string {
+	return []string{"ID"}
+}
+
+func (i deletedTodoItem) Rows() [][]string {
+	return [][]string{{i.Id}}
+}
+
+func (i deletedTodoItem) String() string {
+	return fmt.Sprintf("Todo item with ID %s has been deleted.", i.Id)
+}
+
+// deletedTodoItems is the result of deleting every todo item.
+type deletedTodoItems struct{}
+
+func (deletedTodoItems) String() string {
+	return "All todo items have been deleted."
+}
+
+// syncResult is the result of syncing offline changes.
+type syncResult struct {
+	Synced    int               `json:"synced"`
+	Conflicts []syncConflict    `json:"conflicts"`
+	IDs       map[string]string `json:"ids"`
+}
+
+// syncConflict is an offline change conflicting with the state of the service.
+// nolint: golint, stylecheck
+type syncConflict struct {
+	Operation string `json:"operation"`
+	Id        string `json:"id"`
+	Title     string `json:"title"`
+	Reason    string `json:"reason"`
+}
+
+func newSyncResult(result offline.SyncResult) syncResult {
+	conflicts := make([]syncConflict, 0, len(result.Conflicts))
+
+	for _, conflict := range result.Conflicts {
+		operation := conflict.Operation
+
+		var title string
+
+		if operation.Title != nil {
+			title = *operation.Title
+		} else if operation.Base != nil {
+			title = operation.Base.Title
+		}
+
+		conflicts = append(conflicts, syncConflict{
+			Operation: operation.Kind,
+			Id:        operation.ID,
+			Title:     title,
+			Reason:    conflict.Reason,
+		})
+	}
+
+	return syncResult{
+		Synced:    result.Synced,
+		Conflicts: conflicts,
+		IDs:       result.IDs,
+	}
+}
+
+func (r syncResult) Header() []string {
+	return []string{"Operation", "ID", "Title", "Reason"}
+}
+
+func (r syncResult) Rows() [][]string {
+	rows := make([][]string, 0, len(r.Conflicts))
+
+	for _, conflict := range r.Conflicts {
+		rows = append(rows, []string{conflict.Operation, conflict.Id, conflict.Title, conflict.Reason})
+	}
+
+	return rows
+}
+
+func (r syncResult) String() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "%d offline change(s) have been synced.", r.Synced)
+
+	localIDs := make([]string, 0, len(r.IDs))
+	for localID := range r.IDs {
+		localIDs = append(localIDs, localID)
+	}
+
+	sort.Strings(localIDs)
+
+	for _, localID := range localIDs {
+		fmt.Fprintf(&b, "\nTodo item with local ID %s has been added with ID %s.", localID, r.IDs[localID])
+	}
+
+	for _, conflict := range r.Conflicts {
+		fmt.Fprintf(
+			&b,
+			"\nConflict: %s of %s (%q) has been dropped: %s",
+			conflict.Operation, conflict.Id, conflict.Title, conflict.Reason,
+		)
+	}
+
+	return b.String()
+}
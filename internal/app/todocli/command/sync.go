@@ -0,0 +1,58 @@
+package command
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/offline"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
+)
+
+type syncOptions struct {
+	client  *offline.Client
+	printer output.Printer
+	timeout time.Duration
+}
+
+// NewSyncCommand creates a new cobra.Command for sending offline changes to the service.
+func NewSyncCommand(c Context) *cobra.Command {
+	options := syncOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Send changes made while the service was unreachable",
+		Long: `Send changes made while the service was unreachable.
+
+Items are added and updated in the order the changes were made.
+Changes of items deleted or changed on the service in the meantime are reported as conflicts and dropped.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			options.client = c.GetOfflineClient()
+			options.printer = c.GetPrinter()
+			options.timeout = c.GetTimeout()
+
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runSync(options)
+		},
+	}
+
+	return cmd
+}
+
+func runSync(options syncOptions) error {
+	result, err := options.client.Sync(context.Background(), options.timeout)
+	if err != nil {
+		// Report the changes synced before the error
+		if result.Synced > 0 || len(result.Conflicts) > 0 {
+			_ = options.printer.Print(newSyncResult(result))
+		}
+
+		return err
+	}
+
+	return options.printer.Print(newSyncResult(result))
+}
@@ -0,0 +1,59 @@
+package command
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/cobra"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/tui"
+)
+
+type tuiOptions struct {
+	pollInterval time.Duration
+	client       todov1.TodoListServiceClient
+	timeout      time.Duration
+}
+
+// NewTUICommand creates a new cobra.Command for managing todo items in an interactive terminal user interface.
+func NewTUICommand(c Context) *cobra.Command {
+	options := tuiOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Manage todo items in an interactive terminal user interface",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if options.pollInterval <= 0 {
+				return errors.New("poll interval must be positive")
+			}
+
+			// Warnings about offline changes would break the screen
+			options.client = c.GetOfflineClient().Remote()
+			options.timeout = c.GetTimeout()
+
+			cmd.SilenceErrors = true
+			cmd.SilenceUsage = true
+
+			return runTUI(options)
+		},
+	}
+
+	cmd.Flags().DurationVar(&options.pollInterval, "poll-interval", 2*time.Second, "Time between refreshes of the list")
+
+	return cmd
+}
+
+func runTUI(options tuiOptions) error {
+	config := tui.Config{
+		Client:       options.client,
+		Timeout:      options.timeout,
+		PollInterval: options.pollInterval,
+	}
+
+	return tui.Run(context.Background(), config, os.Stdin, os.Stdout)
+}
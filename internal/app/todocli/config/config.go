@@ -0,0 +1,236 @@
+// Package config manages the todocli configuration file holding named contexts (backends to connect to).
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"emperror.dev/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultContextName is the name of the context used when the configuration has none.
+const DefaultContextName = "default"
+
+// Config is the todocli configuration.
+type Config struct {
+	// CurrentContext is the name of the context commands use by default.
+	CurrentContext string `yaml:"currentContext,omitempty"`
+
+	// Contexts are the named backends todocli can connect to.
+	Contexts map[string]Context `yaml:"contexts,omitempty"`
+}
+
+// Context describes how to connect to a todo service.
+type Context struct {
+	// Address of the todo service.
+	// The HTTP and GraphQL transports also accept a URL (eg. https://todo.example.com/api).
+	Address string `yaml:"address,omitempty"`
+
+	// Transport used to talk to the service (grpc, http or graphql). Defaults to grpc.
+	Transport string `yaml:"transport,omitempty"`
+
+	// TLS configures the connection to the service. TLS is enabled when any of the files are set.
+	TLS TLSConfig `yaml:"tls,omitempty"`
+
+	// Token is sent as a bearer token with every request.
+	Token string `yaml:"token,omitempty"`
+
+	// Insecure allows sending the token over a plaintext connection to a service that is not on a loopback address.
+	Insecure bool `yaml:"insecure,omitempty"`
+
+	// Timeout of requests (including retries).
+	Timeout time.Duration `yaml:"timeout,omitempty"`
+
+	// Retries is the number of times requests failing with an Unavailable status are retried (with backoff).
+	Retries *int `yaml:"retries,omitempty"`
+
+	// Tracing configures exporting request traces to an OpenCensus agent.
+	Tracing TracingConfig `yaml:"tracing,omitempty"`
+}
+
+// TLSConfig configures the TLS connection to a service.
+type TLSConfig struct {
+	// CA is the certificate authority file for verifying the service certificate.
+	CA string `yaml:"ca,omitempty"`
+
+	// Cert is the client certificate file for mutual TLS.
+	Cert string `yaml:"cert,omitempty"`
+
+	// Key is the client key file for mutual TLS.
+	Key string `yaml:"key,omitempty"`
+}
+
+// Enabled reports whether TLS is enabled.
+func (c TLSConfig) Enabled() bool {
+	return c.CA != "" || c.Cert != "" || c.Key != ""
+}
+
+// TracingConfig configures exporting request traces.
+type TracingConfig struct {
+	// Enabled enables exporting traces.
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// AgentAddress is the address of the OpenCensus agent (the exporter default is used when empty).
+	AgentAddress string `yaml:"agentAddress,omitempty"`
+}
+
+// Transports supported by todocli.
+const (
+	TransportGRPC    = "grpc"
+	TransportHTTP    = "http"
+	TransportGraphQL = "graphql"
+)
+
+// DefaultAddress returns the default address of the service for a transport.
+func DefaultAddress(transport string) string {
+	switch transport {
+	case TransportHTTP, TransportGraphQL:
+		return "127.0.0.1:8000"
+
+	default:
+		return "127.0.0.1:8001"
+	}
+}
+
+// MaxRetries is the maximum number of retries supported by gRPC.
+const MaxRetries = 4
+
+// DefaultContext returns the context used when none is configured.
+func DefaultContext() Context {
+	retries := 3
+
+	return Context{
+		Address: DefaultAddress(TransportGRPC),
+		Timeout: time.Second,
+		Retries: &retries,
+	}
+}
+
+// Validate validates the context.
+func (c Context) Validate() error {
+	if c.Timeout <= 0 {
+		return errors.New("timeout must be positive")
+	}
+
+	if c.Retries != nil && (*c.Retries < 0 || *c.Retries > MaxRetries) {
+		return errors.Errorf("retries must be between 0 and %d", MaxRetries)
+	}
+
+	switch c.Transport {
+	case "", TransportGRPC, TransportHTTP, TransportGraphQL:
+
+	default:
+		return errors.Errorf("unknown transport %q (must be grpc, http or graphql)", c.Transport)
+	}
+
+	return nil
+}
+
+// DefaultPath returns the default location of the configuration file
+// ($XDG_CONFIG_HOME/todocli/config.yaml or ~/.config/todocli/config.yaml).
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", errors.WrapIf(err, "failed to determine config file location")
+		}
+
+		dir = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(dir, "todocli", "config.yaml"), nil
+}
+
+// Load reads the configuration from a file. A missing file results in an empty configuration.
+func Load(path string) (Config, error) {
+	var config Config
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return config, errors.WrapIfWithDetails(err, "failed to read config file", "path", path)
+	}
+
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return config, errors.WrapIfWithDetails(err, "failed to parse config file", "path", path)
+	}
+
+	return config, nil
+}
+
+// Save writes the configuration to a file.
+// The file is only readable by the current user, because contexts may contain tokens.
+func (c Config) Save(path string) error {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return errors.WrapIf(err, "failed to encode config")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to create config directory", "path", path)
+	}
+
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.WrapIfWithDetails(err, "failed to write config file", "path", path)
+	}
+
+	return nil
+}
+
+// Context returns a named context.
+// An empty name selects the current context, or the default one when the configuration has no contexts.
+func (c Config) Context(name string) (Context, error) {
+	if name == "" {
+		name = c.CurrentContext
+	}
+
+	if name == "" || (name == DefaultContextName && len(c.Contexts) == 0) {
+		return DefaultContext(), nil
+	}
+
+	context, ok := c.Contexts[name]
+	if !ok {
+		return Context{}, errors.Errorf("context %q does not exist", name)
+	}
+
+	return context.withDefaults(), nil
+}
+
+// withDefaults fills settings missing from a context with their default values.
+func (c Context) withDefaults() Context {
+	defaults := DefaultContext()
+
+	if c.Address == "" {
+		c.Address = DefaultAddress(c.Transport)
+	}
+
+	if c.Timeout == 0 {
+		c.Timeout = defaults.Timeout
+	}
+
+	if c.Retries == nil {
+		c.Retries = defaults.Retries
+	}
+
+	return c
+}
+
+// ContextNames returns the names of the configured contexts in alphabetical order.
+func (c Config) ContextNames() []string {
+	names := make([]string, 0, len(c.Contexts))
+
+	for name := range c.Contexts {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
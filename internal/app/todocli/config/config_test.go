@@ -0,0 +1,118 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_Missing(t *testing.T) {
+	config, err := Load(filepath.Join(t.TempDir(), "config.yaml"))
+	require.NoError(t, err)
+
+	assert.Equal(t, Config{}, config)
+}
+
+func TestConfig_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "todocli", "config.yaml")
+
+	config := Config{
+		CurrentContext: "staging",
+		Contexts: map[string]Context{
+			"local": {Address: "127.0.0.1:8001"},
+			"staging": {
+				Address: "todo.staging.example.com:443",
+				TLS:     TLSConfig{CA: "ca.pem"},
+				Token:   "secret",
+				Timeout: 5 * time.Second,
+				Tracing: TracingConfig{Enabled: true, AgentAddress: "127.0.0.1:55678"},
+			},
+		},
+	}
+
+	require.NoError(t, config.Save(path))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(data), "timeout: 5s")
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, config, loaded)
+}
+
+func TestConfig_Context(t *testing.T) {
+	noRetries := 0
+
+	config := Config{
+		CurrentContext: "local",
+		Contexts: map[string]Context{
+			"local":   {Address: "127.0.0.1:9001"},
+			"staging": {Address: "todo.staging.example.com:443", Timeout: 5 * time.Second, Retries: &noRetries},
+		},
+	}
+
+	context, err := config.Context("")
+	require.NoError(t, err)
+
+	assert.Equal(t, Context{Address: "127.0.0.1:9001", Timeout: time.Second, Retries: DefaultContext().Retries}, context)
+
+	context, err = config.Context("staging")
+	require.NoError(t, err)
+
+	expected := Context{Address: "todo.staging.example.com:443", Timeout: 5 * time.Second, Retries: &noRetries}
+
+	assert.Equal(t, expected, context)
+
+	_, err = config.Context("production")
+	assert.Error(t, err)
+
+	context, err = Config{}.Context("")
+	require.NoError(t, err)
+
+	assert.Equal(t, DefaultContext(), context)
+}
+
+func TestContext_Validate(t *testing.T) {
+	retries := func(n int) *int { return &n }
+
+	tests := map[string]struct {
+		context Context
+		valid   bool
+	}{
+		"default":          {DefaultContext(), true},
+		"noRetries":        {Context{Timeout: time.Second, Retries: retries(0)}, true},
+		"maxRetries":       {Context{Timeout: time.Second, Retries: retries(MaxRetries)}, true},
+		"tooManyRetries":   {Context{Timeout: time.Second, Retries: retries(MaxRetries + 1)}, false},
+		"negativeRetries":  {Context{Timeout: time.Second, Retries: retries(-1)}, false},
+		"noTimeout":        {Context{}, false},
+		"httpTransport":    {Context{Timeout: time.Second, Transport: TransportHTTP}, true},
+		"unknownTransport": {Context{Timeout: time.Second, Transport: "smtp"}, false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.context.Validate()
+
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -1,67 +1,351 @@
 package todocli
 
 import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
 	"contrib.go.opencensus.io/exporter/ocagent"
 	"emperror.dev/errors"
 	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"go.opencensus.io/plugin/ocgrpc"
+	"go.opencensus.io/plugin/ochttp"
 	"go.opencensus.io/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/command"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/config"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/offline"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/transport"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/tlsconfig"
 )
 
 // Configure configures a root command.
+//
+// Settings are taken from flags, TODOCLI_* environment variables and the selected context of the config file
+// (in this order of precedence).
+// The connection to the service is only established when a command makes its first request.
 func Configure(rootCmd *cobra.Command) {
-	var address string
-
 	flags := rootCmd.PersistentFlags()
 
-	flags.StringVar(&address, "address", "127.0.0.1:8001", "Todo service address")
+	flags.String("config", "", "Config file (default ~/.config/todocli/config.yaml)")
+	flags.String("context", "", "Context to use (default is the current context)")
+	flags.String("address", "", "Todo service address or URL (default 127.0.0.1:8001, or 127.0.0.1:8000 for HTTP)")
+	flags.String("transport", "", "Transport used to talk to the service: grpc, http or graphql (default grpc)")
+	flags.String("tls-ca", "", "Certificate authority file for verifying the service certificate (enables TLS)")
+	flags.String("tls-cert", "", "Client certificate file for mutual TLS (enables TLS)")
+	flags.String("tls-key", "", "Client key file for mutual TLS (enables TLS)")
+	flags.Duration("timeout", 0, "Request timeout including retries (default 1s)")
+	flags.Int("retries", 0, "Number of retries of requests failing with an Unavailable status (default 3)")
+	flags.String("token", "", "Token sent with every request")
+	flags.Bool("insecure", false, "Send the token over plaintext connections to non-loopback addresses")
+	flags.Bool("tracing", false, "Export request traces to an OpenCensus agent")
+	flags.String("agent-address", "", "OpenCensus agent address (default is the exporter default)")
+	flags.StringP("output", "o", output.FormatTable, "Output format (table, json, yaml, csv or template=<go template>)")
 
 	c := &context{}
+	conn := &connection{}
 
-	var grpcConn *grpc.ClientConn
 	var ocagentExporter *ocagent.Exporter
 
-	rootCmd.PersistentPreRunE = func(_ *cobra.Command, _ []string) error {
-		conn, err := grpc.Dial(
-			address,
-			grpc.WithInsecure(),
-			grpc.WithStatsHandler(&ocgrpc.ClientHandler{
-				StartOptions: trace.StartOptions{
-					Sampler:  trace.AlwaysSample(),
-					SpanKind: trace.SpanKindClient,
-				},
-			}),
-		)
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+		outputFormat, _ := setting(flags, "output", "OUTPUT")
+
+		printer, err := output.NewPrinter(outputFormat, cmd.OutOrStdout())
 		if err != nil {
-			return errors.WrapIf(err, "failed to dial service")
+			return err
 		}
 
-		// Configure OpenCensus exporter
-		exporter, err := ocagent.NewExporter(ocagent.WithServiceName("todocli"), ocagent.WithInsecure())
+		c.printer = printer
+
+		c.configPath, err = configPath(flags)
 		if err != nil {
-			return errors.WrapIf(err, "failed to create exporter")
+			return err
 		}
 
-		ocagentExporter = exporter
+		c.todoContext = todoContextResolver(flags, c.configPath)
+		c.flagContext = func() (config.Context, error) {
+			return contextFromFlags(flags)
+		}
+
+		c.client = offline.NewClient(conn, offlineStoreResolver(c.todoContext), conn.unreachable, cmd.ErrOrStderr())
+
+		conn.connect = func() (todov1.TodoListServiceClient, *grpc.ClientConn, error) {
+			todoContext, err := c.todoContext()
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if err := checkTokenTransport(todoContext); err != nil {
+				return nil, nil, err
+			}
+
+			var tracing bool
+
+			if todoContext.Tracing.Enabled {
+				exporter, err := newExporter(todoContext.Tracing)
+				if err != nil {
+					// Tracing is optional: requests are made without it
+					warnTracingUnavailable(cmd.ErrOrStderr(), err)
+				} else {
+					ocagentExporter = exporter
+					tracing = true
+				}
+			}
 
-		trace.RegisterExporter(exporter)
+			switch todoContext.Transport {
+			case config.TransportHTTP, config.TransportGraphQL:
+				httpClient, err := newHTTPClient(todoContext, tracing)
+				if err != nil {
+					return nil, nil, err
+				}
 
-		grpcConn = conn
+				if todoContext.Transport == config.TransportGraphQL {
+					return transport.NewGraphQLClient(serviceURL(todoContext)+"/graphql", httpClient), nil, nil
+				}
 
-		c.client = todov1.NewTodoListServiceClient(conn)
+				return transport.NewHTTPClient(serviceURL(todoContext), httpClient), nil, nil
+
+			default:
+				dialOptions, err := newDialOptions(todoContext)
+				if err != nil {
+					return nil, nil, err
+				}
+
+				if tracing {
+					dialOptions = append(dialOptions, grpc.WithStatsHandler(&ocgrpc.ClientHandler{
+						StartOptions: traceStartOptions(),
+					}))
+				}
+
+				conn, err := grpc.Dial(todoContext.Address, dialOptions...)
+				if err != nil {
+					return nil, nil, errors.WrapIf(err, "failed to dial service")
+				}
+
+				return todov1.NewTodoListServiceClient(conn), conn, nil
+			}
+		}
 
 		return nil
 	}
 
 	rootCmd.PersistentPostRunE = func(_ *cobra.Command, _ []string) error {
-		ocagentExporter.Flush()
+		if ocagentExporter != nil {
+			ocagentExporter.Flush()
+		}
 
-		return grpcConn.Close()
+		return conn.Close()
 	}
 
 	command.AddCommands(rootCmd, c)
 }
+
+// todoContextResolver returns a function resolving the context settings (once).
+func todoContextResolver(flags *pflag.FlagSet, configPath string) func() (config.Context, error) {
+	var once sync.Once
+	var todoContext config.Context
+	var err error
+
+	return func() (config.Context, error) {
+		once.Do(func() {
+			var cfg config.Config
+
+			cfg, err = config.Load(configPath)
+			if err != nil {
+				return
+			}
+
+			todoContext, err = resolveContext(flags, cfg)
+		})
+
+		return todoContext, err
+	}
+}
+
+// offlineStoreResolver returns a function returning the offline store of the service of a context.
+func offlineStoreResolver(todoContext func() (config.Context, error)) func() (offline.Store, error) {
+	return func() (offline.Store, error) {
+		todoContext, err := todoContext()
+		if err != nil {
+			return offline.Store{}, err
+		}
+
+		path, err := offline.DefaultPath(todoContext.Address)
+		if err != nil {
+			return offline.Store{}, err
+		}
+
+		return offline.NewStore(path), nil
+	}
+}
+
+// checkTokenTransport refuses to send the token of a context over a plaintext connection,
+// unless the service is on a loopback address or the context is explicitly insecure.
+func checkTokenTransport(todoContext config.Context) error {
+	if todoContext.Token == "" || todoContext.Insecure {
+		return nil
+	}
+
+	secure := todoContext.TLS.Enabled()
+	host := todoContext.Address
+
+	switch todoContext.Transport {
+	case config.TransportHTTP, config.TransportGraphQL:
+		u, err := url.Parse(serviceURL(todoContext))
+		if err != nil {
+			return errors.WrapIf(err, "invalid service address")
+		}
+
+		secure = u.Scheme == "https"
+		host = u.Hostname()
+
+	default:
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+
+	if secure || host == "localhost" || net.ParseIP(host).IsLoopback() {
+		return nil
+	}
+
+	return errors.Errorf(
+		"refusing to send the token to %s over a plaintext connection (enable TLS or use --insecure)",
+		host,
+	)
+}
+
+// newDialOptions returns the options for connecting to the service of a context.
+func newDialOptions(todoContext config.Context) ([]grpc.DialOption, error) {
+	dialOptions := []grpc.DialOption{grpc.WithInsecure()}
+
+	if todoContext.TLS.Enabled() {
+		tlsConfig, err := tlsconfig.NewClientConfig(todoContext.TLS.CA, todoContext.TLS.Cert, todoContext.TLS.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		dialOptions = []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	}
+
+	if todoContext.Token != "" {
+		dialOptions = append(dialOptions, grpc.WithPerRPCCredentials(tokenCredentials{
+			token:  todoContext.Token,
+			secure: todoContext.TLS.Enabled(),
+		}))
+	}
+
+	var retries int
+	if todoContext.Retries != nil {
+		retries = *todoContext.Retries
+	}
+
+	dialOptions = append(dialOptions, grpc.WithDefaultServiceConfig(retryServiceConfig(retries)))
+
+	// Retry policies do not apply to connection failures: wait for the connection (until the timeout) instead
+	if retries > 0 {
+		dialOptions = append(
+			dialOptions,
+			grpc.WithDefaultCallOptions(grpc.WaitForReady(true)),
+			grpc.WithConnectParams(grpc.ConnectParams{
+				Backoff: backoff.Config{
+					BaseDelay:  100 * time.Millisecond,
+					Multiplier: 2,
+					Jitter:     0.2,
+					MaxDelay:   time.Second,
+				},
+				MinConnectTimeout: time.Second,
+			}),
+		)
+	}
+
+	return dialOptions, nil
+}
+
+// serviceURL returns the base URL of the HTTP API of the service of a context.
+// Addresses with a scheme are used as is.
+func serviceURL(todoContext config.Context) string {
+	if strings.Contains(todoContext.Address, "://") {
+		return strings.TrimSuffix(todoContext.Address, "/")
+	}
+
+	if todoContext.TLS.Enabled() {
+		return "https://" + todoContext.Address
+	}
+
+	return "http://" + todoContext.Address
+}
+
+// newHTTPClient returns an HTTP client for the service of a context.
+func newHTTPClient(todoContext config.Context, tracing bool) (*http.Client, error) {
+	httpTransport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if todoContext.TLS.Enabled() {
+		tlsConfig, err := tlsconfig.NewClientConfig(todoContext.TLS.CA, todoContext.TLS.Cert, todoContext.TLS.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		httpTransport.TLSClientConfig = tlsConfig
+	}
+
+	var roundTripper http.RoundTripper = httpTransport
+
+	if todoContext.Token != "" {
+		roundTripper = transport.WithToken(roundTripper, todoContext.Token)
+	}
+
+	if todoContext.Retries != nil && *todoContext.Retries > 0 {
+		roundTripper = transport.WithRetries(roundTripper, *todoContext.Retries)
+	}
+
+	if tracing {
+		roundTripper = &ochttp.Transport{
+			Base:         roundTripper,
+			StartOptions: traceStartOptions(),
+		}
+	}
+
+	return &http.Client{Transport: roundTripper}, nil
+}
+
+func traceStartOptions() trace.StartOptions {
+	return trace.StartOptions{
+		Sampler:  trace.AlwaysSample(),
+		SpanKind: trace.SpanKindClient,
+	}
+}
+
+// newExporter creates and registers an OpenCensus agent exporter.
+func newExporter(config config.TracingConfig) (*ocagent.Exporter, error) {
+	options := []ocagent.ExporterOption{ocagent.WithServiceName("todocli"), ocagent.WithInsecure()}
+
+	if config.AgentAddress != "" {
+		options = append(options, ocagent.WithAddress(config.AgentAddress))
+	}
+
+	exporter, err := ocagent.NewExporter(options...)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create exporter")
+	}
+
+	trace.RegisterExporter(exporter)
+
+	return exporter, nil
+}
+
+// warnTracingUnavailable tells the user that requests are made without tracing.
+func warnTracingUnavailable(w io.Writer, err error) {
+	fmt.Fprintf(w, "Warning: %s (tracing is disabled)\n", err)
+}
@@ -0,0 +1,58 @@
+package todocli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/config"
+)
+
+func TestCheckTokenTransport(t *testing.T) {
+	grpcTLS := tokenContext(config.TransportGRPC, "todo.example.com:8001")
+	grpcTLS.TLS.CA = "ca.pem"
+
+	grpcInsecure := tokenContext(config.TransportGRPC, "todo.example.com:8001")
+	grpcInsecure.Insecure = true
+
+	httpTLS := tokenContext(config.TransportHTTP, "todo.example.com")
+	httpTLS.TLS.CA = "ca.pem"
+
+	tests := map[string]struct {
+		context config.Context
+		allowed bool
+	}{
+		"noToken":            {config.Context{Address: "todo.example.com:8001"}, true},
+		"grpcLoopback":       {tokenContext(config.TransportGRPC, "127.0.0.1:8001"), true},
+		"grpcLoopbackIPv6":   {tokenContext(config.TransportGRPC, "[::1]:8001"), true},
+		"grpcLocalhost":      {tokenContext(config.TransportGRPC, "localhost:8001"), true},
+		"grpcRemote":         {tokenContext(config.TransportGRPC, "todo.example.com:8001"), false},
+		"grpcRemoteTLS":      {grpcTLS, true},
+		"grpcRemoteInsecure": {grpcInsecure, true},
+		"httpLoopback":       {tokenContext(config.TransportHTTP, "127.0.0.1:8000"), true},
+		"httpRemote":         {tokenContext(config.TransportHTTP, "todo.example.com:8000"), false},
+		"httpRemoteURL":      {tokenContext(config.TransportHTTP, "http://todo.example.com/api"), false},
+		"httpsRemoteURL":     {tokenContext(config.TransportHTTP, "https://todo.example.com/api"), true},
+		"httpRemoteTLS":      {httpTLS, true},
+		"graphqlLocalhost":   {tokenContext(config.TransportGraphQL, "localhost"), true},
+		"graphqlRemote":      {tokenContext(config.TransportGraphQL, "todo.example.com"), false},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := checkTokenTransport(test.context)
+
+			if test.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func tokenContext(transport string, address string) config.Context {
+	return config.Context{Transport: transport, Address: address, Token: "secret"}
+}
@@ -0,0 +1,166 @@
+package todocli
+
+import (
+	stdcontext "context"
+	"fmt"
+	"sync"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/status"
+)
+
+// connection is a todo list client created when the first request is made,
+// so that commands not talking to the service do not connect to it.
+type connection struct {
+	// connect creates a client using the transport of the context.
+	// The gRPC client connection is returned for the gRPC transport.
+	connect func() (todov1.TodoListServiceClient, *grpc.ClientConn, error)
+
+	mu     sync.Mutex
+	client todov1.TodoListServiceClient
+	conn   *grpc.ClientConn
+	err    error
+}
+
+func (c *connection) get() (todov1.TodoListServiceClient, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.client == nil && c.err == nil {
+		c.client, c.conn, c.err = c.connect()
+	}
+
+	return c.client, c.err
+}
+
+func (c *connection) AddItem(
+	ctx stdcontext.Context,
+	in *todov1.AddItemRequest,
+	opts ...grpc.CallOption,
+) (*todov1.AddItemResponse, error) {
+	client, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.AddItem(ctx, in, opts...)
+}
+
+func (c *connection) ListItems(
+	ctx stdcontext.Context,
+	in *todov1.ListItemsRequest,
+	opts ...grpc.CallOption,
+) (*todov1.ListItemsResponse, error) {
+	client, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListItems(ctx, in, opts...)
+}
+
+func (c *connection) DeleteItems(
+	ctx stdcontext.Context,
+	in *todov1.DeleteItemsRequest,
+	opts ...grpc.CallOption,
+) (*todov1.DeleteItemsResponse, error) {
+	client, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.DeleteItems(ctx, in, opts...)
+}
+
+func (c *connection) GetItem(
+	ctx stdcontext.Context,
+	in *todov1.GetItemRequest,
+	opts ...grpc.CallOption,
+) (*todov1.GetItemResponse, error) {
+	client, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.GetItem(ctx, in, opts...)
+}
+
+func (c *connection) UpdateItem(
+	ctx stdcontext.Context,
+	in *todov1.UpdateItemRequest,
+	opts ...grpc.CallOption,
+) (*todov1.UpdateItemResponse, error) {
+	client, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.UpdateItem(ctx, in, opts...)
+}
+
+func (c *connection) DeleteItem(
+	ctx stdcontext.Context,
+	in *todov1.DeleteItemRequest,
+	opts ...grpc.CallOption,
+) (*todov1.DeleteItemResponse, error) {
+	client, err := c.get()
+	if err != nil {
+		return nil, err
+	}
+
+	return client.DeleteItem(ctx, in, opts...)
+}
+
+// Close closes the gRPC client connection if it has been dialed.
+func (c *connection) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		return nil
+	}
+
+	return c.conn.Close()
+}
+
+// unreachable tells whether a request failed because the service could not be reached.
+func (c *connection) unreachable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return true
+
+	case codes.DeadlineExceeded:
+		// gRPC requests waiting for the connection (see newDialOptions) time out
+		c.mu.Lock()
+		conn := c.conn
+		c.mu.Unlock()
+
+		return conn != nil && conn.GetState() != connectivity.Ready
+
+	default:
+		return false
+	}
+}
+
+// retryServiceConfig returns a gRPC service config retrying requests failing with an Unavailable status.
+func retryServiceConfig(retries int) string {
+	if retries == 0 {
+		return "{}"
+	}
+
+	return fmt.Sprintf(`{
+	"methodConfig": [{
+		"name": [{"service": "todo.v1.TodoListService"}],
+		"retryPolicy": {
+			"maxAttempts": %d,
+			"initialBackoff": "0.1s",
+			"maxBackoff": "1s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`, retries+1)
+}
@@ -1,13 +1,53 @@
 package todocli
 
 import (
+	"time"
+
 	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/config"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/offline"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/output"
 )
 
 type context struct {
-	client todov1.TodoListServiceClient
+	client     *offline.Client
+	printer    output.Printer
+	configPath string
+
+	// todoContext returns the resolved context settings (see resolveContext)
+	todoContext func() (config.Context, error)
+
+	// flagContext returns a new context with the settings given as flags (see contextFromFlags)
+	flagContext func() (config.Context, error)
 }
 
 func (c *context) GetTodoClient() todov1.TodoListServiceClient {
 	return c.client
 }
+
+func (c *context) GetOfflineClient() *offline.Client {
+	return c.client
+}
+
+func (c *context) GetPrinter() output.Printer {
+	return c.printer
+}
+
+func (c *context) GetTimeout() time.Duration {
+	todoContext, err := c.todoContext()
+	if err != nil {
+		// The error is returned by the client when making a request
+		return config.DefaultContext().Timeout
+	}
+
+	return todoContext.Timeout
+}
+
+func (c *context) GetConfigPath() string {
+	return c.configPath
+}
+
+func (c *context) NewContextFromFlags() (config.Context, error) {
+	return c.flagContext()
+}
@@ -0,0 +1,21 @@
+package todocli
+
+import (
+	stdcontext "context"
+)
+
+// tokenCredentials sends a bearer token with every request.
+type tokenCredentials struct {
+	token  string
+	secure bool
+}
+
+func (c tokenCredentials) GetRequestMetadata(_ stdcontext.Context, _ ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + c.token}, nil
+}
+
+// RequireTransportSecurity allows sending tokens over plaintext connections
+// (only used for services on a loopback address or contexts that are explicitly insecure, see checkTokenTransport).
+func (c tokenCredentials) RequireTransportSecurity() bool {
+	return c.secure
+}
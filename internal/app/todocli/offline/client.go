@@ -0,0 +1,409 @@
+package offline
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/goph/idgen/ulidgen"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+)
+
+// Client is a todo list client falling back to the local copy of items when the service is unreachable.
+//
+// While the service is unreachable items are listed from the local copy,
+// and added or updated items are queued until they are synced (see Sync).
+// Other requests fail as usual.
+//
+// The service assigns the IDs of new items, so items added offline get a local ID until they are synced.
+type Client struct {
+	todov1.TodoListServiceClient
+
+	store       func() (Store, error)
+	unreachable func(err error) bool
+	warnings    io.Writer
+	ids         *ulidgen.Generator
+}
+
+// NewClient returns a new Client.
+//
+// The store is resolved when it is first needed. Unreachable tells whether a request failed
+// because the service could not be reached. Warnings about using the local copy are written to warnings.
+func NewClient(
+	client todov1.TodoListServiceClient,
+	store func() (Store, error),
+	unreachable func(err error) bool,
+	warnings io.Writer,
+) *Client {
+	return &Client{
+		TodoListServiceClient: client,
+
+		store:       store,
+		unreachable: unreachable,
+		warnings:    warnings,
+		ids:         ulidgen.NewGenerator(),
+	}
+}
+
+// Remote returns the client sending requests to the service.
+func (c *Client) Remote() todov1.TodoListServiceClient {
+	return c.TodoListServiceClient
+}
+
+func (c *Client) load() (Store, State, error) {
+	store, err := c.store()
+	if err != nil {
+		return store, State{}, err
+	}
+
+	state, err := store.Load()
+
+	return store, state, err
+}
+
+// update changes the local copy after a successful request.
+// The local copy is only used while the service is unreachable, so errors are ignored.
+func (c *Client) update(fn func(state *State)) {
+	store, err := c.store()
+	if err != nil {
+		return
+	}
+
+	_ = store.Update(func(state *State) error {
+		fn(state)
+
+		return nil
+	})
+}
+
+func (c *Client) warnPending(state State) {
+	if len(state.Pending) > 0 {
+		fmt.Fprintf(
+			c.warnings,
+			"Warning: %d offline change(s) have not been synced yet (run \"todocli sync\")\n",
+			len(state.Pending),
+		)
+	}
+}
+
+// ListItems implements the todov1.TodoListServiceClient interface.
+//
+// Items are listed with the changes waiting to be synced applied.
+func (c *Client) ListItems(
+	ctx context.Context,
+	in *todov1.ListItemsRequest,
+	opts ...grpc.CallOption,
+) (*todov1.ListItemsResponse, error) {
+	resp, err := c.TodoListServiceClient.ListItems(ctx, in, opts...)
+	if err != nil {
+		if !c.unreachable(err) {
+			return nil, err
+		}
+
+		_, state, serr := c.load()
+		if serr != nil {
+			return nil, err
+		}
+
+		if state.SyncedAt.IsZero() && len(state.Pending) == 0 {
+			return nil, err
+		}
+
+		fmt.Fprintf(
+			c.warnings,
+			"Warning: service is unreachable, listing items cached at %s\n",
+			state.SyncedAt.Local().Format(time.RFC3339),
+		)
+
+		return &todov1.ListItemsResponse{Items: toProtoItems(state.View())}, nil
+	}
+
+	var state State
+
+	c.update(func(s *State) {
+		s.Items = fromProtoItems(resp.GetItems())
+		s.SyncedAt = time.Now()
+
+		state = *s
+	})
+
+	if len(state.Pending) == 0 {
+		return resp, nil
+	}
+
+	c.warnPending(state)
+
+	return &todov1.ListItemsResponse{Items: toProtoItems(state.View())}, nil
+}
+
+// GetItem implements the todov1.TodoListServiceClient interface.
+func (c *Client) GetItem(
+	ctx context.Context,
+	in *todov1.GetItemRequest,
+	opts ...grpc.CallOption,
+) (*todov1.GetItemResponse, error) {
+	resp, err := c.TodoListServiceClient.GetItem(ctx, in, opts...)
+	if err != nil {
+		if !c.unreachable(err) && status.Code(err) != codes.NotFound {
+			return nil, err
+		}
+
+		_, state, serr := c.load()
+		if serr != nil {
+			return nil, err
+		}
+
+		// Items added offline are not known by the service yet
+		if status.Code(err) == codes.NotFound && !state.IsLocal(in.GetId()) {
+			return nil, err
+		}
+
+		item, ok := state.Find(in.GetId())
+		if !ok {
+			return nil, err
+		}
+
+		return &todov1.GetItemResponse{Item: toProtoItem(item)}, nil
+	}
+
+	return resp, nil
+}
+
+// AddItem implements the todov1.TodoListServiceClient interface.
+func (c *Client) AddItem(
+	ctx context.Context,
+	in *todov1.AddItemRequest,
+	opts ...grpc.CallOption,
+) (*todov1.AddItemResponse, error) {
+	resp, err := c.TodoListServiceClient.AddItem(ctx, in, opts...)
+	if err != nil {
+		if !c.unreachable(err) {
+			return nil, err
+		}
+
+		id, gerr := c.ids.Generate()
+		if gerr != nil {
+			return nil, err
+		}
+
+		title := in.GetTitle()
+		order := in.GetOrder()
+
+		operation := Operation{
+			Kind:     OperationAdd,
+			ID:       id,
+			Title:    &title,
+			Order:    &order,
+			QueuedAt: time.Now(),
+		}
+
+		if qerr := c.queue(operation); qerr != nil {
+			return nil, err
+		}
+
+		fmt.Fprintln(c.warnings, "Warning: service is unreachable, the item will be added when synced (run \"todocli sync\")")
+
+		return &todov1.AddItemResponse{Item: &todov1.TodoItem{Id: id, Title: title, Order: order}}, nil
+	}
+
+	c.update(func(state *State) {
+		state.Items = append(state.Items, fromProtoItem(resp.GetItem()))
+	})
+
+	return resp, nil
+}
+
+// UpdateItem implements the todov1.TodoListServiceClient interface.
+//
+// Changes of items added offline are queued, since the service does not know those items yet.
+func (c *Client) UpdateItem(
+	ctx context.Context,
+	in *todov1.UpdateItemRequest,
+	opts ...grpc.CallOption,
+) (*todov1.UpdateItemResponse, error) {
+	_, state, err := c.load()
+	if err == nil && state.IsLocal(in.GetId()) {
+		if resp, ok := c.queueUpdate(state, in); ok {
+			return resp, nil
+		}
+	}
+
+	resp, err := c.TodoListServiceClient.UpdateItem(ctx, in, opts...)
+	if err != nil {
+		if !c.unreachable(err) {
+			return nil, err
+		}
+
+		_, state, serr := c.load()
+		if serr != nil {
+			return nil, err
+		}
+
+		if resp, ok := c.queueUpdate(state, in); ok {
+			return resp, nil
+		}
+
+		return nil, err
+	}
+
+	c.update(func(state *State) {
+		for i, item := range state.Items {
+			if item.ID == resp.GetItem().GetId() {
+				state.Items[i] = fromProtoItem(resp.GetItem())
+			}
+		}
+	})
+
+	return resp, nil
+}
+
+// queueUpdate queues a change of an item known by the local copy.
+func (c *Client) queueUpdate(state State, in *todov1.UpdateItemRequest) (*todov1.UpdateItemResponse, bool) {
+	item, ok := state.Find(in.GetId())
+	if !ok {
+		return nil, false
+	}
+
+	operation := Operation{
+		Kind:     OperationUpdate,
+		ID:       in.GetId(),
+		QueuedAt: time.Now(),
+	}
+
+	// Items added offline cannot be changed by others until they are synced
+	if !state.IsLocal(in.GetId()) {
+		base := item
+		operation.Base = &base
+	}
+
+	if in.Title != nil {
+		operation.Title = &in.Title.Value
+	}
+
+	if in.Completed != nil {
+		operation.Completed = &in.Completed.Value
+	}
+
+	if in.Order != nil {
+		operation.Order = &in.Order.Value
+	}
+
+	if err := c.queue(operation); err != nil {
+		return nil, false
+	}
+
+	fmt.Fprintln(c.warnings, "Warning: the change will be sent to the service when synced (run \"todocli sync\")")
+
+	operation.apply(&item)
+
+	return &todov1.UpdateItemResponse{Item: toProtoItem(item)}, true
+}
+
+// DeleteItem implements the todov1.TodoListServiceClient interface.
+func (c *Client) DeleteItem(
+	ctx context.Context,
+	in *todov1.DeleteItemRequest,
+	opts ...grpc.CallOption,
+) (*todov1.DeleteItemResponse, error) {
+	_, state, err := c.load()
+	if err == nil && state.IsLocal(in.GetId()) {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			"todo item %s has not been synced yet (run \"todocli sync\")",
+			in.GetId(),
+		)
+	}
+
+	resp, err := c.TodoListServiceClient.DeleteItem(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.update(func(state *State) {
+		items := state.Items[:0]
+
+		for _, item := range state.Items {
+			if item.ID != in.GetId() {
+				items = append(items, item)
+			}
+		}
+
+		state.Items = items
+	})
+
+	return resp, nil
+}
+
+// DeleteItems implements the todov1.TodoListServiceClient interface.
+func (c *Client) DeleteItems(
+	ctx context.Context,
+	in *todov1.DeleteItemsRequest,
+	opts ...grpc.CallOption,
+) (*todov1.DeleteItemsResponse, error) {
+	resp, err := c.TodoListServiceClient.DeleteItems(ctx, in, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	c.update(func(state *State) {
+		state.Items = nil
+	})
+
+	return resp, nil
+}
+
+func (c *Client) queue(operation Operation) error {
+	store, err := c.store()
+	if err != nil {
+		return err
+	}
+
+	return store.Update(func(state *State) error {
+		state.Pending = append(state.Pending, operation)
+
+		return nil
+	})
+}
+
+func fromProtoItem(item *todov1.TodoItem) Item {
+	return Item{
+		ID:        item.GetId(),
+		Title:     item.GetTitle(),
+		Completed: item.GetCompleted(),
+		Order:     item.GetOrder(),
+	}
+}
+
+func fromProtoItems(items []*todov1.TodoItem) []Item {
+	result := make([]Item, 0, len(items))
+
+	for _, item := range items {
+		result = append(result, fromProtoItem(item))
+	}
+
+	return result
+}
+
+func toProtoItem(item Item) *todov1.TodoItem {
+	return &todov1.TodoItem{
+		Id:        item.ID,
+		Title:     item.Title,
+		Completed: item.Completed,
+		Order:     item.Order,
+	}
+}
+
+func toProtoItems(items []Item) []*todov1.TodoItem {
+	result := make([]*todov1.TodoItem, 0, len(items))
+
+	for _, item := range items {
+		result = append(result, toProtoItem(item))
+	}
+
+	return result
+}
@@ -0,0 +1,224 @@
+package offline
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+)
+
+// fakeService is an in-memory todo list service that can be made unreachable.
+type fakeService struct {
+	todov1.TodoListServiceClient
+
+	offline bool
+	items   []*todov1.TodoItem
+	nextID  int
+
+	// lostResponses makes added items time out after they are added
+	lostResponses bool
+}
+
+func (s *fakeService) unavailable() error {
+	return status.Error(codes.Unavailable, "connection refused")
+}
+
+func (s *fakeService) find(id string) *todov1.TodoItem {
+	for _, item := range s.items {
+		if item.Id == id {
+			return item
+		}
+	}
+
+	return nil
+}
+
+func copyItem(item *todov1.TodoItem) *todov1.TodoItem {
+	return &todov1.TodoItem{Id: item.Id, Title: item.Title, Completed: item.Completed, Order: item.Order}
+}
+
+func (s *fakeService) ListItems(
+	_ context.Context,
+	_ *todov1.ListItemsRequest,
+	_ ...grpc.CallOption,
+) (*todov1.ListItemsResponse, error) {
+	if s.offline {
+		return nil, s.unavailable()
+	}
+
+	items := make([]*todov1.TodoItem, 0, len(s.items))
+
+	for _, item := range s.items {
+		items = append(items, copyItem(item))
+	}
+
+	return &todov1.ListItemsResponse{Items: items}, nil
+}
+
+func (s *fakeService) GetItem(
+	_ context.Context,
+	in *todov1.GetItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.GetItemResponse, error) {
+	if s.offline {
+		return nil, s.unavailable()
+	}
+
+	item := s.find(in.GetId())
+	if item == nil {
+		return nil, status.Error(codes.NotFound, "todo item not found")
+	}
+
+	return &todov1.GetItemResponse{Item: copyItem(item)}, nil
+}
+
+func (s *fakeService) AddItem(
+	_ context.Context,
+	in *todov1.AddItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.AddItemResponse, error) {
+	if s.offline {
+		return nil, s.unavailable()
+	}
+
+	if in.GetTitle() == "" {
+		return nil, status.Error(codes.InvalidArgument, "title cannot be empty")
+	}
+
+	s.nextID++
+
+	item := &todov1.TodoItem{Id: strconv.Itoa(s.nextID), Title: in.GetTitle(), Order: in.GetOrder()}
+	s.items = append(s.items, item)
+
+	if s.lostResponses {
+		return nil, status.Error(codes.DeadlineExceeded, "context deadline exceeded")
+	}
+
+	return &todov1.AddItemResponse{Item: copyItem(item)}, nil
+}
+
+func (s *fakeService) UpdateItem(
+	_ context.Context,
+	in *todov1.UpdateItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.UpdateItemResponse, error) {
+	if s.offline {
+		return nil, s.unavailable()
+	}
+
+	item := s.find(in.GetId())
+	if item == nil {
+		return nil, status.Error(codes.NotFound, "todo item not found")
+	}
+
+	if in.Title != nil {
+		item.Title = in.Title.Value
+	}
+
+	if in.Completed != nil {
+		item.Completed = in.Completed.Value
+	}
+
+	if in.Order != nil {
+		item.Order = in.Order.Value
+	}
+
+	return &todov1.UpdateItemResponse{Item: copyItem(item)}, nil
+}
+
+func newTestClient(t *testing.T, service *fakeService) (*Client, Store, *bytes.Buffer) {
+	t.Helper()
+
+	store := NewStore(filepath.Join(t.TempDir(), "service.json"))
+	warnings := new(bytes.Buffer)
+
+	client := NewClient(
+		service,
+		func() (Store, error) { return store, nil },
+		func(err error) bool { return status.Code(err) == codes.Unavailable },
+		warnings,
+	)
+
+	return client, store, warnings
+}
+
+func listTitles(t *testing.T, client todov1.TodoListServiceClient) map[string]bool {
+	t.Helper()
+
+	resp, err := client.ListItems(context.Background(), &todov1.ListItemsRequest{})
+	require.NoError(t, err)
+
+	titles := make(map[string]bool)
+
+	for _, item := range resp.GetItems() {
+		titles[item.GetTitle()] = item.GetCompleted()
+	}
+
+	return titles
+}
+
+func TestClient_Offline(t *testing.T) {
+	service := &fakeService{}
+	client, store, warnings := newTestClient(t, service)
+	ctx := context.Background()
+
+	_, err := client.AddItem(ctx, &todov1.AddItemRequest{Title: "Buy milk"})
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]bool{"Buy milk": false}, listTitles(t, client))
+	assert.Empty(t, warnings.String())
+
+	service.offline = true
+
+	resp, err := client.AddItem(ctx, &todov1.AddItemRequest{Title: "Call mom"})
+	require.NoError(t, err)
+
+	localID := resp.GetItem().GetId()
+	assert.NotEmpty(t, localID)
+
+	_, err = client.UpdateItem(ctx, &todov1.UpdateItemRequest{Id: "1", Completed: &wrappers.BoolValue{Value: true}})
+	require.NoError(t, err)
+
+	_, err = client.UpdateItem(ctx, &todov1.UpdateItemRequest{Id: "unknown", Completed: &wrappers.BoolValue{Value: true}})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	assert.Equal(t, map[string]bool{"Buy milk": true, "Call mom": false}, listTitles(t, client))
+	assert.Contains(t, warnings.String(), "service is unreachable")
+
+	// Items added offline are not sent to the service even if it is reachable
+	service.offline = false
+
+	_, err = client.UpdateItem(ctx, &todov1.UpdateItemRequest{Id: localID, Completed: &wrappers.BoolValue{Value: true}})
+	require.NoError(t, err)
+
+	_, err = client.DeleteItem(ctx, &todov1.DeleteItemRequest{Id: localID})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	assert.Equal(t, map[string]bool{"Buy milk": true, "Call mom": true}, listTitles(t, client))
+	assert.Contains(t, warnings.String(), "3 offline change(s) have not been synced yet")
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, state.Pending, 3)
+
+	// Nothing has reached the service yet
+	assert.Equal(t, map[string]bool{"Buy milk": false}, listTitles(t, service))
+}
+
+func TestClient_Offline_NoCache(t *testing.T) {
+	service := &fakeService{offline: true}
+	client, _, _ := newTestClient(t, service)
+
+	_, err := client.ListItems(context.Background(), &todov1.ListItemsRequest{})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
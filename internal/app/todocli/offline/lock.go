@@ -0,0 +1,30 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package offline
+
+import (
+	"os"
+
+	"emperror.dev/errors"
+	"golang.org/x/sys/unix"
+)
+
+// lockFile takes an exclusive lock on a file, waiting for other processes holding it.
+// The lock is released by the returned function (or when the process exits).
+func lockFile(path string) (func(), error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to open lock file", "path", path)
+	}
+
+	err = unix.Flock(int(file.Fd()), unix.LOCK_EX)
+	if err != nil {
+		_ = file.Close()
+
+		return nil, errors.WrapIfWithDetails(err, "failed to lock offline state", "path", path)
+	}
+
+	// Closing the file releases the lock
+	return func() { _ = file.Close() }, nil
+}
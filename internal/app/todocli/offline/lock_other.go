@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package offline
+
+// lockFile does nothing: locking is not supported on this platform,
+// so concurrent todocli processes may overwrite each other's changes.
+func lockFile(_ string) (func(), error) {
+	return func() {}, nil
+}
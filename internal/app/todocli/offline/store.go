@@ -0,0 +1,220 @@
+// Package offline keeps a local copy of todo items so that todocli can be used while the service is unreachable.
+package offline
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Item is the local copy of a todo item.
+type Item struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+	Order     int32  `json:"order"`
+}
+
+// Operation kinds.
+const (
+	OperationAdd    = "add"
+	OperationUpdate = "update"
+)
+
+// Operation is a change made while the service was unreachable.
+type Operation struct {
+	Kind string `json:"kind"`
+
+	// ID is the ID of the changed item.
+	// Items added offline get a local ID that is replaced by the ID assigned by the service when synced.
+	ID string `json:"id"`
+
+	Title     *string `json:"title,omitempty"`
+	Completed *bool   `json:"completed,omitempty"`
+	Order     *int32  `json:"order,omitempty"`
+
+	// Base is the item as it was known when an update was queued (used for detecting conflicting changes).
+	Base *Item `json:"base,omitempty"`
+
+	// Sent tells whether the operation has been sent to the service before.
+	// The outcome of an earlier attempt is unknown: the service may have applied it even if the request failed.
+	Sent bool `json:"sent,omitempty"`
+
+	QueuedAt time.Time `json:"queuedAt"`
+}
+
+func (o Operation) apply(item *Item) {
+	if o.Title != nil {
+		item.Title = *o.Title
+	}
+
+	if o.Completed != nil {
+		item.Completed = *o.Completed
+	}
+
+	if o.Order != nil {
+		item.Order = *o.Order
+	}
+}
+
+// State is the local copy of the todo items of a service and the operations waiting to be sent to it.
+type State struct {
+	// Items are the items as last received from the service.
+	Items []Item `json:"items"`
+
+	// SyncedAt is the time items were last received from the service.
+	SyncedAt time.Time `json:"syncedAt"`
+
+	// Pending operations in the order they were made.
+	Pending []Operation `json:"pending,omitempty"`
+}
+
+// View returns the items with the pending operations applied.
+func (s State) View() []Item {
+	items := make([]Item, len(s.Items))
+	copy(items, s.Items)
+
+	for _, operation := range s.Pending {
+		switch operation.Kind {
+		case OperationAdd:
+			item := Item{ID: operation.ID}
+			operation.apply(&item)
+
+			items = append(items, item)
+
+		case OperationUpdate:
+			for i := range items {
+				if items[i].ID == operation.ID {
+					operation.apply(&items[i])
+				}
+			}
+		}
+	}
+
+	return items
+}
+
+// Find returns an item (with the pending operations applied).
+func (s State) Find(id string) (Item, bool) {
+	for _, item := range s.View() {
+		if item.ID == id {
+			return item, true
+		}
+	}
+
+	return Item{}, false
+}
+
+// IsLocal tells whether an item has been added offline and has not been synced yet.
+func (s State) IsLocal(id string) bool {
+	for _, operation := range s.Pending {
+		if operation.Kind == OperationAdd && operation.ID == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Store persists the state in a file.
+type Store struct {
+	path string
+}
+
+// NewStore returns a new Store.
+func NewStore(path string) Store {
+	return Store{path: path}
+}
+
+// DefaultPath returns the default path of the state of a service (in the user cache directory).
+func DefaultPath(address string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", errors.WrapIf(err, "failed to determine cache directory")
+	}
+
+	name := strings.NewReplacer(":", "_", "/", "_", "\\", "_").Replace(address)
+
+	return filepath.Join(dir, "todocli", name+".json"), nil
+}
+
+// Load reads the state. A missing file results in an empty state.
+func (s Store) Load() (State, error) {
+	var state State
+
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, errors.WrapIfWithDetails(err, "failed to read offline state", "path", s.path)
+	}
+
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		return state, errors.WrapIfWithDetails(err, "failed to parse offline state", "path", s.path)
+	}
+
+	return state, nil
+}
+
+// Save writes the state.
+func (s Store) Save(state State) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errors.WrapIf(err, "failed to encode offline state")
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return errors.WrapIf(err, "failed to create offline state directory")
+	}
+
+	// Replace the file at once, so that an interrupted write does not lose queued operations
+	tmp := s.path + ".tmp"
+
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return errors.WrapIf(err, "failed to write offline state")
+	}
+
+	return errors.WrapIf(os.Rename(tmp, s.path), "failed to write offline state")
+}
+
+// Lock locks the state, so that other processes cannot change it until it is unlocked.
+// It waits for other processes holding the lock.
+func (s Store) Lock() (unlock func(), err error) {
+	err = os.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create offline state directory")
+	}
+
+	return lockFile(s.path + ".lock")
+}
+
+// Update changes the state.
+// The state is locked while it is changed, so that concurrent updates (eg. of another process) are not lost.
+func (s Store) Update(fn func(state *State) error) error {
+	unlock, err := s.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	state, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	err = fn(&state)
+	if err != nil {
+		return err
+	}
+
+	return s.Save(state)
+}
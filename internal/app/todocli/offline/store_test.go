@@ -0,0 +1,127 @@
+package offline
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stringPtr(s string) *string { return &s }
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestState_View(t *testing.T) {
+	state := State{
+		Items: []Item{
+			{ID: "1", Title: "Buy milk"},
+			{ID: "2", Title: "Walk the dog"},
+		},
+		Pending: []Operation{
+			{Kind: OperationAdd, ID: "local", Title: stringPtr("Call mom")},
+			{Kind: OperationUpdate, ID: "1", Completed: boolPtr(true)},
+			{Kind: OperationUpdate, ID: "local", Title: stringPtr("Call dad")},
+		},
+	}
+
+	expected := []Item{
+		{ID: "1", Title: "Buy milk", Completed: true},
+		{ID: "2", Title: "Walk the dog"},
+		{ID: "local", Title: "Call dad"},
+	}
+
+	assert.Equal(t, expected, state.View())
+
+	// The items received from the service are not changed
+	assert.False(t, state.Items[0].Completed)
+
+	assert.True(t, state.IsLocal("local"))
+	assert.False(t, state.IsLocal("1"))
+
+	item, ok := state.Find("local")
+	require.True(t, ok)
+	assert.Equal(t, "Call dad", item.Title)
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "todocli", "service.json"))
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, State{}, state)
+
+	expected := State{
+		Items:    []Item{{ID: "1", Title: "Buy milk", Order: 1}},
+		SyncedAt: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		Pending: []Operation{
+			{
+				Kind:     OperationAdd,
+				ID:       "local",
+				Title:    stringPtr("Call mom"),
+				QueuedAt: time.Date(2021, 1, 2, 3, 4, 6, 0, time.UTC),
+			},
+		},
+	}
+
+	require.NoError(t, store.Save(expected))
+
+	state, err = store.Load()
+	require.NoError(t, err)
+	assert.Equal(t, expected, state)
+
+	err = store.Update(func(state *State) error {
+		state.Pending = nil
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	state, err = store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, state.Pending)
+}
+
+func TestStore_ConcurrentUpdates(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "service.json"))
+
+	const updates = 20
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < updates; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			err := store.Update(func(state *State) error {
+				state.Pending = append(state.Pending, Operation{Kind: OperationAdd})
+
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+
+	wg.Wait()
+
+	state, err := store.Load()
+	require.NoError(t, err)
+
+	// No update is lost
+	assert.Len(t, state.Pending, updates)
+}
+
+func TestDefaultPath(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", "/tmp/cache")
+	t.Setenv("HOME", "/tmp/home")
+
+	path, err := DefaultPath("todo.example.com:443")
+	require.NoError(t, err)
+
+	assert.Equal(t, "todo.example.com_443.json", filepath.Base(path))
+	assert.Equal(t, "todocli", filepath.Base(filepath.Dir(path)))
+}
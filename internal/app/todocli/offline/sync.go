@@ -0,0 +1,266 @@
+package offline
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+)
+
+// SyncResult is the outcome of sending the queued operations to the service.
+type SyncResult struct {
+	// Synced is the number of operations sent to the service.
+	Synced int
+
+	// Conflicts are the operations that have been dropped instead of being sent to the service.
+	Conflicts []Conflict
+
+	// IDs maps the local IDs of items added offline to the IDs assigned by the service.
+	IDs map[string]string
+}
+
+// Conflict is an operation conflicting with the state of the service.
+type Conflict struct {
+	Operation Operation
+	Reason    string
+}
+
+// Sync sends the queued operations to the service in the order they were made,
+// then refreshes the local copy of items. Timeout applies to every request.
+//
+// Updates of items deleted or changed on the service (since they were last listed) are reported as conflicts:
+// the service wins and the update is dropped. Operations rejected by the service are reported as conflicts as well.
+// When the service becomes unreachable, the remaining operations are kept for the next sync.
+// Items that may have been added by an interrupted sync are looked up (by title and order) instead of added again.
+func (c *Client) Sync(ctx context.Context, timeout time.Duration) (SyncResult, error) {
+	result, err := c.sync(ctx, timeout)
+	if err != nil {
+		return result, err
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Refreshes the local copy
+	_, err = c.ListItems(reqCtx, &todov1.ListItemsRequest{})
+
+	return result, err
+}
+
+// sync sends the queued operations to the service.
+// The state is locked in the meantime, so that concurrent syncs do not send the same operations.
+func (c *Client) sync(ctx context.Context, timeout time.Duration) (SyncResult, error) {
+	result := SyncResult{IDs: make(map[string]string)}
+
+	// Local IDs of items that could not be added
+	dropped := make(map[string]bool)
+
+	store, err := c.store()
+	if err != nil {
+		return result, err
+	}
+
+	unlock, err := store.Lock()
+	if err != nil {
+		return result, err
+	}
+	defer unlock()
+
+	for {
+		state, err := store.Load()
+		if err != nil {
+			return result, err
+		}
+
+		if len(state.Pending) == 0 {
+			break
+		}
+
+		operation := state.Pending[0]
+
+		var id, conflict string
+
+		if dropped[operation.ID] {
+			conflict = "item could not be added to the service"
+		} else {
+			if operation.Kind == OperationAdd && !operation.Sent {
+				// Remember the attempt: the item may be added even if the response is lost
+				state.Pending[0].Sent = true
+
+				err = store.Save(state)
+				if err != nil {
+					return result, err
+				}
+			}
+
+			id, conflict, err = c.send(ctx, timeout, operation)
+			if err != nil {
+				return result, err
+			}
+		}
+
+		if conflict != "" {
+			result.Conflicts = append(result.Conflicts, Conflict{Operation: operation, Reason: conflict})
+		} else {
+			result.Synced++
+		}
+
+		// Save the progress after every operation, so that nothing is sent twice
+		state.Pending = state.Pending[1:]
+
+		if operation.Kind == OperationAdd {
+			if conflict != "" {
+				dropped[operation.ID] = true
+			} else {
+				result.IDs[operation.ID] = id
+
+				for i := range state.Pending {
+					if state.Pending[i].ID == operation.ID {
+						state.Pending[i].ID = id
+					}
+				}
+			}
+		}
+
+		err = store.Save(state)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// send sends an operation to the service.
+// It returns the ID of the item on the service or the reason of a conflict.
+// An error is only returned when the operation should be retried (eg. the service is unreachable).
+func (c *Client) send(ctx context.Context, timeout time.Duration, operation Operation) (string, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	client := c.TodoListServiceClient
+
+	switch operation.Kind {
+	case OperationAdd:
+		req := &todov1.AddItemRequest{}
+
+		if operation.Title != nil {
+			req.Title = *operation.Title
+		}
+
+		if operation.Order != nil {
+			req.Order = *operation.Order
+		}
+
+		if operation.Sent {
+			id, err := findAdded(ctx, client, req)
+			if err != nil {
+				return c.rejected(err)
+			}
+
+			if id != "" {
+				return id, "", nil
+			}
+		}
+
+		resp, err := client.AddItem(ctx, req)
+		if err != nil {
+			return c.rejected(err)
+		}
+
+		return resp.GetItem().GetId(), "", nil
+
+	case OperationUpdate:
+		current, err := client.GetItem(ctx, &todov1.GetItemRequest{Id: operation.ID})
+		if status.Code(err) == codes.NotFound {
+			return "", "item has been deleted on the service", nil
+		}
+		if err != nil {
+			return c.rejected(err)
+		}
+
+		if conflict := changedOnService(operation, fromProtoItem(current.GetItem())); conflict != "" {
+			return "", conflict, nil
+		}
+
+		req := &todov1.UpdateItemRequest{Id: operation.ID}
+
+		if operation.Title != nil {
+			req.Title = &wrappers.StringValue{Value: *operation.Title}
+		}
+
+		if operation.Completed != nil {
+			req.Completed = &wrappers.BoolValue{Value: *operation.Completed}
+		}
+
+		if operation.Order != nil {
+			req.Order = &wrappers.Int32Value{Value: *operation.Order}
+		}
+
+		_, err = client.UpdateItem(ctx, req)
+		if err != nil {
+			return c.rejected(err)
+		}
+
+		return operation.ID, "", nil
+
+	default:
+		return "", "unknown operation " + operation.Kind, nil
+	}
+}
+
+// findAdded returns the ID of an item added by an earlier attempt of an add request (if any).
+func findAdded(ctx context.Context, client todov1.TodoListServiceClient, req *todov1.AddItemRequest) (string, error) {
+	resp, err := client.ListItems(ctx, &todov1.ListItemsRequest{})
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range resp.GetItems() {
+		if item.GetTitle() == req.GetTitle() && item.GetOrder() == req.GetOrder() {
+			return item.GetId(), nil
+		}
+	}
+
+	return "", nil
+}
+
+// rejected returns the error of a failed request if the request should be retried,
+// otherwise the error is returned as the reason of a conflict.
+func (c *Client) rejected(err error) (string, string, error) {
+	if c.unreachable(err) {
+		return "", "", err
+	}
+
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.NotFound, codes.AlreadyExists, codes.OutOfRange:
+		return "", "rejected by the service: " + status.Convert(err).Message(), nil
+
+	default:
+		return "", "", err
+	}
+}
+
+// changedOnService returns a conflict if a field changed by an update has been changed on the service as well.
+// Marking an item as complete (or incomplete) never conflicts: either both sides made the same change
+// or the update does not change the item.
+func changedOnService(operation Operation, current Item) string {
+	base := operation.Base
+	if base == nil {
+		return ""
+	}
+
+	if operation.Title != nil && current.Title != base.Title && current.Title != *operation.Title {
+		return "title has been changed on the service"
+	}
+
+	if operation.Order != nil && current.Order != base.Order && current.Order != *operation.Order {
+		return "order has been changed on the service"
+	}
+
+	return ""
+}
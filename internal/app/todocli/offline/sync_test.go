@@ -0,0 +1,147 @@
+package offline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+)
+
+func TestClient_Sync(t *testing.T) {
+	service := &fakeService{}
+	client, store, _ := newTestClient(t, service)
+	ctx := context.Background()
+
+	for _, title := range []string{"Buy milk", "Walk the dog", "Water the plants"} {
+		_, err := client.AddItem(ctx, &todov1.AddItemRequest{Title: title})
+		require.NoError(t, err)
+	}
+
+	_, err := client.ListItems(ctx, &todov1.ListItemsRequest{})
+	require.NoError(t, err)
+
+	service.offline = true
+
+	resp, err := client.AddItem(ctx, &todov1.AddItemRequest{Title: "Call mom"})
+	require.NoError(t, err)
+
+	localID := resp.GetItem().GetId()
+
+	updates := []*todov1.UpdateItemRequest{
+		{Id: localID, Completed: &wrappers.BoolValue{Value: true}},
+		{Id: "1", Completed: &wrappers.BoolValue{Value: true}},
+		{Id: "2", Title: &wrappers.StringValue{Value: "Walk the cat"}},
+		{Id: "3", Title: &wrappers.StringValue{Value: "Water the flowers"}},
+	}
+
+	for _, update := range updates {
+		_, err := client.UpdateItem(ctx, update)
+		require.NoError(t, err)
+	}
+
+	_, err = client.AddItem(ctx, &todov1.AddItemRequest{})
+	require.NoError(t, err)
+
+	// Changes made by others in the meantime
+	service.offline = false
+	service.items[1].Title = "Walk the dogs"
+	service.items = service.items[:2]
+
+	result, err := client.Sync(ctx, time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, result.Synced)
+	assert.Equal(t, map[string]string{localID: "4"}, result.IDs)
+
+	require.Len(t, result.Conflicts, 3)
+	assert.Equal(t, "title has been changed on the service", result.Conflicts[0].Reason)
+	assert.Equal(t, "item has been deleted on the service", result.Conflicts[1].Reason)
+	assert.Equal(t, "rejected by the service: title cannot be empty", result.Conflicts[2].Reason)
+
+	expected := map[string]bool{
+		"Buy milk":      true,
+		"Walk the dogs": false,
+		"Call mom":      true,
+	}
+
+	assert.Equal(t, expected, listTitles(t, service))
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Empty(t, state.Pending)
+	assert.Len(t, state.Items, 3)
+}
+
+func TestClient_Sync_Unreachable(t *testing.T) {
+	service := &fakeService{offline: true}
+	client, store, _ := newTestClient(t, service)
+	ctx := context.Background()
+
+	resp, err := client.AddItem(ctx, &todov1.AddItemRequest{Title: "Call mom"})
+	require.NoError(t, err)
+
+	localID := resp.GetItem().GetId()
+
+	_, err = client.UpdateItem(ctx, &todov1.UpdateItemRequest{Id: localID, Completed: &wrappers.BoolValue{Value: true}})
+	require.NoError(t, err)
+
+	result, err := client.Sync(ctx, time.Second)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 0, result.Synced)
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	assert.Len(t, state.Pending, 2)
+
+	service.offline = false
+
+	result, err = client.Sync(ctx, time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Synced)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, map[string]bool{"Call mom": true}, listTitles(t, service))
+}
+
+func TestClient_Sync_LostResponse(t *testing.T) {
+	service := &fakeService{offline: true}
+	client, store, _ := newTestClient(t, service)
+	ctx := context.Background()
+
+	for _, title := range []string{"Call mom", "Buy milk"} {
+		_, err := client.AddItem(ctx, &todov1.AddItemRequest{Title: title, Order: 1})
+		require.NoError(t, err)
+	}
+
+	// The first item is added, but the response never arrives
+	service.offline = false
+	service.lostResponses = true
+
+	_, err := client.Sync(ctx, time.Second)
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	state, err := store.Load()
+	require.NoError(t, err)
+	require.Len(t, state.Pending, 2)
+	assert.True(t, state.Pending[0].Sent)
+	assert.False(t, state.Pending[1].Sent)
+
+	service.lostResponses = false
+
+	result, err := client.Sync(ctx, time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, result.Synced)
+	assert.Len(t, result.IDs, 2)
+
+	// The item is not added twice
+	require.Len(t, service.items, 2)
+	assert.Equal(t, map[string]bool{"Call mom": false, "Buy milk": false}, listTitles(t, service))
+}
@@ -0,0 +1,170 @@
+// Package output renders command results in human and machine-readable formats.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/template"
+
+	"emperror.dev/errors"
+	"github.com/ghodss/yaml"
+	"github.com/olekukonko/tablewriter"
+)
+
+// Supported output formats.
+const (
+	FormatTable    = "table"
+	FormatJSON     = "json"
+	FormatYAML     = "yaml"
+	FormatCSV      = "csv"
+	FormatTemplate = "template"
+)
+
+// Table is implemented by results that can be rendered as a table (table and CSV formats).
+type Table interface {
+	Header() []string
+	Rows() [][]string
+}
+
+// Printer renders command results.
+type Printer interface {
+	Print(v interface{}) error
+}
+
+// NewPrinter returns a Printer for an output format.
+//
+// Templates are given as "template=<template>" and are executed for every element of list results.
+func NewPrinter(format string, w io.Writer) (Printer, error) {
+	switch format {
+	case FormatTable, "":
+		return tablePrinter{w: w}, nil
+
+	case FormatJSON:
+		return jsonPrinter{w: w}, nil
+
+	case FormatYAML:
+		return yamlPrinter{w: w}, nil
+
+	case FormatCSV:
+		return csvPrinter{w: w}, nil
+	}
+
+	if text := strings.TrimPrefix(format, FormatTemplate+"="); text != format {
+		tpl, err := template.New("output").Parse(text)
+		if err != nil {
+			return nil, errors.WrapIf(err, "invalid output template")
+		}
+
+		return templatePrinter{w: w, tpl: tpl}, nil
+	}
+
+	return nil, errors.Errorf(
+		"unsupported output format %q (supported formats: table, json, yaml, csv, template=<template>)",
+		format,
+	)
+}
+
+// tablePrinter renders results for humans:
+// messages (fmt.Stringer results) are printed as they are, other results are rendered as a table.
+type tablePrinter struct {
+	w io.Writer
+}
+
+func (p tablePrinter) Print(v interface{}) error {
+	switch r := v.(type) {
+	case fmt.Stringer:
+		_, err := fmt.Fprintln(p.w, r.String())
+
+		return err
+
+	case Table:
+		table := tablewriter.NewWriter(p.w)
+		table.SetHeader(r.Header())
+		table.AppendBulk(r.Rows())
+		table.Render()
+
+		return nil
+
+	default:
+		return errors.Errorf("result cannot be rendered as a table: %T", v)
+	}
+}
+
+type jsonPrinter struct {
+	w io.Writer
+}
+
+func (p jsonPrinter) Print(v interface{}) error {
+	encoder := json.NewEncoder(p.w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
+
+type yamlPrinter struct {
+	w io.Writer
+}
+
+func (p yamlPrinter) Print(v interface{}) error {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return errors.WrapIf(err, "failed to render result as yaml")
+	}
+
+	_, err = p.w.Write(out)
+
+	return err
+}
+
+type csvPrinter struct {
+	w io.Writer
+}
+
+func (p csvPrinter) Print(v interface{}) error {
+	table, ok := v.(Table)
+	if !ok {
+		return errors.Errorf("result cannot be rendered as csv: %T", v)
+	}
+
+	w := csv.NewWriter(p.w)
+
+	_ = w.Write(table.Header())
+	_ = w.WriteAll(table.Rows())
+
+	return w.Error()
+}
+
+type templatePrinter struct {
+	w   io.Writer
+	tpl *template.Template
+}
+
+func (p templatePrinter) Print(v interface{}) error {
+	value := reflect.ValueOf(v)
+
+	if value.Kind() != reflect.Slice {
+		return p.execute(v)
+	}
+
+	for i := 0; i < value.Len(); i++ {
+		if err := p.execute(value.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p templatePrinter) execute(v interface{}) error {
+	if err := p.tpl.Execute(p.w, v); err != nil {
+		return errors.WrapIf(err, "failed to render result with template")
+	}
+
+	_, err := fmt.Fprintln(p.w)
+
+	return err
+}
@@ -0,0 +1,123 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nolint: golint, stylecheck
+type item struct {
+	Id    string `json:"id"`
+	Title string `json:"title"`
+}
+
+type items []item
+
+func (items) Header() []string {
+	return []string{"ID", "Title"}
+}
+
+func (l items) Rows() [][]string {
+	rows := make([][]string, 0, len(l))
+
+	for _, i := range l {
+		rows = append(rows, []string{i.Id, i.Title})
+	}
+
+	return rows
+}
+
+type message string
+
+func (m message) String() string {
+	return string(m)
+}
+
+func TestPrinter(t *testing.T) {
+	result := items{{Id: "1", Title: "buy milk"}, {Id: "2", Title: "walk, the dog"}}
+
+	tests := map[string]struct {
+		format   string
+		result   interface{}
+		expected string
+	}{
+		"table": {
+			format: FormatTable,
+			result: result,
+			expected: "+----+---------------+\n" +
+				"| ID |     TITLE     |\n" +
+				"+----+---------------+\n" +
+				"|  1 | buy milk      |\n" +
+				"|  2 | walk, the dog |\n" +
+				"+----+---------------+\n",
+		},
+		"tableMessage": {
+			format:   FormatTable,
+			result:   message("Done."),
+			expected: "Done.\n",
+		},
+		"json": {
+			format: FormatJSON,
+			result: result,
+			expected: "[\n" +
+				"  {\n    \"id\": \"1\",\n    \"title\": \"buy milk\"\n  },\n" +
+				"  {\n    \"id\": \"2\",\n    \"title\": \"walk, the dog\"\n  }\n" +
+				"]\n",
+		},
+		"yaml": {
+			format:   FormatYAML,
+			result:   result,
+			expected: "- id: \"1\"\n  title: buy milk\n- id: \"2\"\n  title: walk, the dog\n",
+		},
+		"csv": {
+			format:   FormatCSV,
+			result:   result,
+			expected: "ID,Title\n1,buy milk\n2,\"walk, the dog\"\n",
+		},
+		"template": {
+			format:   "template={{.Id}}: {{.Title}}",
+			result:   result,
+			expected: "1: buy milk\n2: walk, the dog\n",
+		},
+		"templateSingleResult": {
+			format:   "template={{.Id}}",
+			result:   item{Id: "1"},
+			expected: "1\n",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			printer, err := NewPrinter(test.format, &buf)
+			require.NoError(t, err)
+
+			require.NoError(t, printer.Print(test.result))
+
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+}
+
+func TestPrinter_Unsupported(t *testing.T) {
+	var buf bytes.Buffer
+
+	printer, err := NewPrinter(FormatCSV, &buf)
+	require.NoError(t, err)
+
+	assert.Error(t, printer.Print(message("Done.")))
+}
+
+func TestNewPrinter_Invalid(t *testing.T) {
+	_, err := NewPrinter("xml", nil)
+	assert.Error(t, err)
+
+	_, err = NewPrinter("template={{.Id", nil)
+	assert.Error(t, err)
+}
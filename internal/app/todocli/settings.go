@@ -0,0 +1,124 @@
+package todocli
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/spf13/pflag"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/config"
+)
+
+// envPrefix is the prefix of environment variables overriding settings.
+const envPrefix = "TODOCLI_"
+
+// contextOverrides are context settings that can be overridden by flags and environment variables.
+// Flags take precedence over environment variables.
+// nolint: gochecknoglobals
+var contextOverrides = []struct {
+	flag string
+	env  string
+	set  func(c *config.Context, value string) error
+}{
+	{"transport", "TRANSPORT", func(c *config.Context, v string) error {
+		// Switch to the default address of the new transport unless an address is configured
+		if c.Address == config.DefaultAddress(c.Transport) {
+			c.Address = config.DefaultAddress(v)
+		}
+
+		c.Transport = v
+
+		return nil
+	}},
+	{"address", "ADDRESS", func(c *config.Context, v string) error { c.Address = v; return nil }},
+	{"tls-ca", "TLS_CA", func(c *config.Context, v string) error { c.TLS.CA = v; return nil }},
+	{"tls-cert", "TLS_CERT", func(c *config.Context, v string) error { c.TLS.Cert = v; return nil }},
+	{"tls-key", "TLS_KEY", func(c *config.Context, v string) error { c.TLS.Key = v; return nil }},
+	{"token", "TOKEN", func(c *config.Context, v string) error { c.Token = v; return nil }},
+	{"insecure", "INSECURE", func(c *config.Context, v string) (err error) {
+		c.Insecure, err = strconv.ParseBool(v)
+
+		return errors.WrapIf(err, "invalid insecure setting")
+	}},
+	{"timeout", "TIMEOUT", func(c *config.Context, v string) (err error) {
+		c.Timeout, err = time.ParseDuration(v)
+
+		return errors.WrapIf(err, "invalid timeout")
+	}},
+	{"retries", "RETRIES", func(c *config.Context, v string) error {
+		retries, err := strconv.Atoi(v)
+		c.Retries = &retries
+
+		return errors.WrapIf(err, "invalid retries")
+	}},
+	{"tracing", "TRACING", func(c *config.Context, v string) (err error) {
+		c.Tracing.Enabled, err = strconv.ParseBool(v)
+
+		return errors.WrapIf(err, "invalid tracing setting")
+	}},
+	{"agent-address", "AGENT_ADDRESS", func(c *config.Context, v string) error { c.Tracing.AgentAddress = v; return nil }},
+}
+
+// setting returns the value of a setting from a flag, or from an environment variable when the flag is not set.
+func setting(flags *pflag.FlagSet, flag string, env string) (string, bool) {
+	if f := flags.Lookup(flag); f != nil && f.Changed {
+		return f.Value.String(), true
+	}
+
+	return os.LookupEnv(envPrefix + env)
+}
+
+// configPath returns the location of the configuration file.
+func configPath(flags *pflag.FlagSet) (string, error) {
+	if path, ok := setting(flags, "config", "CONFIG"); ok && path != "" {
+		return path, nil
+	}
+
+	return config.DefaultPath()
+}
+
+// resolveContext returns the context selected by flags, environment variables and the configuration file
+// with overrides applied.
+func resolveContext(flags *pflag.FlagSet, cfg config.Config) (config.Context, error) {
+	name, _ := setting(flags, "context", "CONTEXT")
+
+	context, err := cfg.Context(name)
+	if err != nil {
+		return context, err
+	}
+
+	return applyOverrides(context, func(flag string, env string) (string, bool) {
+		return setting(flags, flag, env)
+	})
+}
+
+// contextFromFlags returns a new context with the settings given as flags.
+func contextFromFlags(flags *pflag.FlagSet) (config.Context, error) {
+	return applyOverrides(config.DefaultContext(), func(flag string, _ string) (string, bool) {
+		if f := flags.Lookup(flag); f != nil && f.Changed {
+			return f.Value.String(), true
+		}
+
+		return "", false
+	})
+}
+
+func applyOverrides(
+	context config.Context,
+	lookup func(flag string, env string) (string, bool),
+) (config.Context, error) {
+	for _, override := range contextOverrides {
+		value, ok := lookup(override.flag, override.env)
+		if !ok {
+			continue
+		}
+
+		if err := override.set(&context, value); err != nil {
+			return context, errors.WithDetails(err, "setting", override.flag)
+		}
+	}
+
+	return context, context.Validate()
+}
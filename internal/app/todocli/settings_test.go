@@ -0,0 +1,138 @@
+package todocli
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/config"
+)
+
+func newTestFlags(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+
+	flags := pflag.NewFlagSet("todocli", pflag.ContinueOnError)
+
+	flags.String("context", "", "")
+	flags.String("address", "", "")
+	flags.String("transport", "", "")
+	flags.String("tls-ca", "", "")
+	flags.Duration("timeout", 0, "")
+	flags.Int("retries", 0, "")
+	flags.String("token", "", "")
+
+	require.NoError(t, flags.Parse(args))
+
+	return flags
+}
+
+func TestResolveContext(t *testing.T) {
+	cfg := config.Config{
+		CurrentContext: "local",
+		Contexts: map[string]config.Context{
+			"local":   {Address: "127.0.0.1:8001"},
+			"staging": {Address: "todo.staging.example.com:443", Token: "secret", Timeout: 5 * time.Second},
+		},
+	}
+
+	t.Run("CurrentContext", func(t *testing.T) {
+		context, err := resolveContext(newTestFlags(t), cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, config.DefaultContext(), context)
+	})
+
+	t.Run("ContextFromEnv", func(t *testing.T) {
+		t.Setenv("TODOCLI_CONTEXT", "staging")
+
+		context, err := resolveContext(newTestFlags(t), cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, "todo.staging.example.com:443", context.Address)
+	})
+
+	t.Run("ContextFromFlag", func(t *testing.T) {
+		t.Setenv("TODOCLI_CONTEXT", "local")
+
+		context, err := resolveContext(newTestFlags(t, "--context", "staging"), cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, "todo.staging.example.com:443", context.Address)
+	})
+
+	t.Run("MissingContext", func(t *testing.T) {
+		_, err := resolveContext(newTestFlags(t, "--context", "production"), cfg)
+		assert.Error(t, err)
+	})
+
+	t.Run("Overrides", func(t *testing.T) {
+		t.Setenv("TODOCLI_ADDRESS", "127.0.0.1:9001")
+		t.Setenv("TODOCLI_TIMEOUT", "3s")
+		t.Setenv("TODOCLI_TOKEN", "token")
+		t.Setenv("TODOCLI_TRACING", "true")
+		t.Setenv("TODOCLI_AGENT_ADDRESS", "127.0.0.1:55678")
+
+		t.Setenv("TODOCLI_RETRIES", "2")
+
+		context, err := resolveContext(newTestFlags(t, "--timeout", "10s", "--tls-ca", "ca.pem", "--retries", "1"), cfg)
+		require.NoError(t, err)
+
+		retries := 1
+
+		expected := config.Context{
+			Address: "127.0.0.1:9001",
+			TLS:     config.TLSConfig{CA: "ca.pem"},
+			Token:   "token",
+			Timeout: 10 * time.Second,
+			Retries: &retries,
+			Tracing: config.TracingConfig{Enabled: true, AgentAddress: "127.0.0.1:55678"},
+		}
+
+		assert.Equal(t, expected, context)
+	})
+
+	t.Run("Transport", func(t *testing.T) {
+		t.Setenv("TODOCLI_TRANSPORT", "http")
+
+		context, err := resolveContext(newTestFlags(t), cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, config.TransportHTTP, context.Transport)
+		assert.Equal(t, config.DefaultAddress(config.TransportHTTP), context.Address)
+
+		context, err = resolveContext(newTestFlags(t, "--context", "staging", "--transport", "graphql"), cfg)
+		require.NoError(t, err)
+
+		assert.Equal(t, config.TransportGraphQL, context.Transport)
+		assert.Equal(t, "todo.staging.example.com:443", context.Address)
+	})
+
+	t.Run("InvalidOverride", func(t *testing.T) {
+		t.Setenv("TODOCLI_TIMEOUT", "soon")
+
+		_, err := resolveContext(newTestFlags(t), cfg)
+		assert.Error(t, err)
+	})
+
+	t.Run("InvalidContext", func(t *testing.T) {
+		_, err := resolveContext(newTestFlags(t, "--retries", "10"), cfg)
+		assert.Error(t, err)
+	})
+}
+
+func TestContextFromFlags(t *testing.T) {
+	t.Setenv("TODOCLI_ADDRESS", "127.0.0.1:9001")
+
+	context, err := contextFromFlags(newTestFlags(t, "--token", "secret", "--timeout", "5s"))
+	require.NoError(t, err)
+
+	expected := config.DefaultContext()
+	expected.Token = "secret"
+	expected.Timeout = 5 * time.Second
+
+	// Environment variables are ignored
+	assert.Equal(t, expected, context)
+}
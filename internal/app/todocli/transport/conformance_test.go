@@ -0,0 +1,163 @@
+package transport_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/sagikazarmark/modern-go-application/internal/app/mga/mgatest"
+	"github.com/sagikazarmark/modern-go-application/internal/app/todocli/transport"
+)
+
+// newTestClients starts an in-process todo service and returns a client for every transport.
+func newTestClients(t *testing.T) map[string]todov1.TodoListServiceClient {
+	t.Helper()
+
+	server := mgatest.NewServer(t, mgatest.Config{})
+
+	return map[string]todov1.TodoListServiceClient{
+		"grpc":    todov1.NewTodoListServiceClient(server.GRPCConn),
+		"http":    transport.NewHTTPClient(server.URL, server.HTTPClient),
+		"graphql": transport.NewGraphQLClient(server.GraphQLURL(), server.HTTPClient),
+	}
+}
+
+// TestConformance checks that every transport behaves the same way (including errors).
+func TestConformance(t *testing.T) {
+	for _, name := range []string{"grpc", "http", "graphql"} {
+		name := name
+
+		t.Run(name, func(t *testing.T) {
+			t.Run("Items", func(t *testing.T) {
+				client := newTestClients(t)[name]
+				ctx := context.Background()
+
+				addResp, err := client.AddItem(ctx, &todov1.AddItemRequest{Title: "Buy milk", Order: 2})
+				require.NoError(t, err)
+
+				item := addResp.GetItem()
+				require.NotEmpty(t, item.GetId())
+				assert.Equal(t, "Buy milk", item.GetTitle())
+				assert.Equal(t, int32(2), item.GetOrder())
+				assert.False(t, item.GetCompleted())
+
+				_, err = client.AddItem(ctx, &todov1.AddItemRequest{Title: "Call mom", Order: 1})
+				require.NoError(t, err)
+
+				getResp, err := client.GetItem(ctx, &todov1.GetItemRequest{Id: item.GetId()})
+				require.NoError(t, err)
+				assert.Equal(t, item.GetTitle(), getResp.GetItem().GetTitle())
+
+				updateResp, err := client.UpdateItem(ctx, &todov1.UpdateItemRequest{
+					Id:        item.GetId(),
+					Title:     &wrappers.StringValue{Value: "Buy oat milk"},
+					Completed: &wrappers.BoolValue{Value: true},
+				})
+				require.NoError(t, err)
+
+				updated := updateResp.GetItem()
+				assert.Equal(t, item.GetId(), updated.GetId())
+				assert.Equal(t, "Buy oat milk", updated.GetTitle())
+				assert.True(t, updated.GetCompleted())
+				assert.Equal(t, int32(2), updated.GetOrder())
+
+				listResp, err := client.ListItems(ctx, &todov1.ListItemsRequest{})
+				require.NoError(t, err)
+
+				titles := make(map[string]bool)
+				for _, item := range listResp.GetItems() {
+					titles[item.GetTitle()] = item.GetCompleted()
+				}
+
+				assert.Equal(t, map[string]bool{"Buy oat milk": true, "Call mom": false}, titles)
+			})
+
+			t.Run("NotFound", func(t *testing.T) {
+				client := newTestClients(t)[name]
+				ctx := context.Background()
+
+				_, err := client.GetItem(ctx, &todov1.GetItemRequest{Id: "unknown"})
+				assertStatus(t, err, codes.NotFound, "get item: item not found")
+
+				_, err = client.UpdateItem(ctx, &todov1.UpdateItemRequest{
+					Id:        "unknown",
+					Completed: &wrappers.BoolValue{Value: true},
+				})
+				assertStatus(t, err, codes.NotFound, "item not found")
+			})
+
+			t.Run("InvalidArgument", func(t *testing.T) {
+				client := newTestClients(t)[name]
+
+				_, err := client.AddItem(context.Background(), &todov1.AddItemRequest{Title: ""})
+				assertStatus(t, err, codes.InvalidArgument, "")
+
+				var violations []*errdetails.BadRequest_FieldViolation
+
+				for _, detail := range status.Convert(err).Details() {
+					if br, ok := detail.(*errdetails.BadRequest); ok {
+						violations = append(violations, br.GetFieldViolations()...)
+					}
+				}
+
+				require.Len(t, violations, 1)
+				assert.Equal(t, "title", violations[0].GetField())
+				assert.Equal(t, "title cannot be empty", violations[0].GetDescription())
+			})
+
+			t.Run("Delete", func(t *testing.T) {
+				client := newTestClients(t)[name]
+				ctx := context.Background()
+
+				addResp, err := client.AddItem(ctx, &todov1.AddItemRequest{Title: "Buy milk"})
+				require.NoError(t, err)
+
+				_, err = client.DeleteItem(ctx, &todov1.DeleteItemRequest{Id: addResp.GetItem().GetId()})
+				if name == "graphql" {
+					// The GraphQL API cannot delete items
+					assertStatus(t, err, codes.Unimplemented, "")
+
+					_, err = client.DeleteItems(ctx, &todov1.DeleteItemsRequest{})
+					assertStatus(t, err, codes.Unimplemented, "")
+
+					return
+				}
+
+				require.NoError(t, err)
+
+				_, err = client.GetItem(ctx, &todov1.GetItemRequest{Id: addResp.GetItem().GetId()})
+				assertStatus(t, err, codes.NotFound, "get item: item not found")
+
+				_, err = client.AddItem(ctx, &todov1.AddItemRequest{Title: "Call mom"})
+				require.NoError(t, err)
+
+				_, err = client.DeleteItems(ctx, &todov1.DeleteItemsRequest{})
+				require.NoError(t, err)
+
+				listResp, err := client.ListItems(ctx, &todov1.ListItemsRequest{})
+				require.NoError(t, err)
+				assert.Empty(t, listResp.GetItems())
+			})
+		})
+	}
+}
+
+func assertStatus(t *testing.T, err error, code codes.Code, message string) {
+	t.Helper()
+
+	require.Error(t, err)
+
+	st := status.Convert(err)
+	assert.Equal(t, code, st.Code(), st.Message())
+
+	if message != "" {
+		assert.Equal(t, message, st.Message())
+	}
+}
@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"emperror.dev/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Errors are converted to gRPC status errors, so that they are handled the same way regardless of the transport.
+
+// requestError converts an error of sending a request (eg. the service is unreachable) to a status error.
+func requestError(err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
+
+// codeFromHTTPStatus returns the status code of an HTTP response status
+// (the opposite of the mapping done by the JSON/REST gateway).
+func codeFromHTTPStatus(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+
+	case http.StatusNotFound:
+		return codes.NotFound
+
+	case http.StatusConflict:
+		return codes.AlreadyExists
+
+	case http.StatusPreconditionFailed:
+		return codes.FailedPrecondition
+
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+
+	case http.StatusInternalServerError:
+		return codes.Internal
+
+	default:
+		return codes.Unknown
+	}
+}
+
+// problemError converts an HTTP error response (an RFC 7807 problem) to a status error.
+func problemError(statusCode int, body []byte) error {
+	var problem struct {
+		Title      string              `json:"title"`
+		Detail     string              `json:"detail"`
+		Violations map[string][]string `json:"violations"`
+	}
+
+	// The response may not be a problem (eg. when returned by a proxy)
+	_ = json.Unmarshal(body, &problem)
+
+	message := problem.Detail
+	if message == "" {
+		message = problem.Title
+	}
+
+	if message == "" {
+		message = http.StatusText(statusCode)
+	}
+
+	return newStatusError(codeFromHTTPStatus(statusCode), message, problem.Violations)
+}
+
+// newStatusError returns a status error. Violations are attached as field violations (like the gRPC API does).
+func newStatusError(code codes.Code, message string, violations map[string][]string) error {
+	st := status.New(code, message)
+
+	if len(violations) == 0 {
+		return st.Err()
+	}
+
+	fields := make([]string, 0, len(violations))
+	for field := range violations {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	br := &errdetails.BadRequest{}
+
+	for _, field := range fields {
+		for _, violation := range violations[field] {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: violation,
+			})
+		}
+	}
+
+	if stWithDetails, err := st.WithDetails(br); err == nil {
+		st = stWithDetails
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,250 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+)
+
+const graphqlTodoItemFields = "id title completed order"
+
+type graphqlClient struct {
+	url    string
+	client *http.Client
+}
+
+// NewGraphQLClient returns a todo list client using the GraphQL API at a URL (eg. http://localhost:8000/graphql).
+//
+// The GraphQL API cannot get or delete items: getting an item lists every item, deleting items is not supported.
+func NewGraphQLClient(url string, client *http.Client) todov1.TodoListServiceClient {
+	return &graphqlClient{
+		url:    url,
+		client: client,
+	}
+}
+
+type graphqlTodoItem struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Completed bool   `json:"completed"`
+	Order     int32  `json:"order"`
+}
+
+func (i graphqlTodoItem) proto() *todov1.TodoItem {
+	return &todov1.TodoItem{
+		Id:        i.ID,
+		Title:     i.Title,
+		Completed: i.Completed,
+		Order:     i.Order,
+	}
+}
+
+type graphqlError struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code       string              `json:"code"`
+		Violations map[string][]string `json:"violations"`
+	} `json:"extensions"`
+}
+
+// err converts a GraphQL error to a status error.
+// The status code is reported by the service as an extension (eg. NOT_FOUND).
+func (e graphqlError) err() error {
+	code := codes.Unknown
+
+	if e.Extensions.Code != "" {
+		var c codes.Code
+
+		if err := c.UnmarshalJSON([]byte(`"` + e.Extensions.Code + `"`)); err == nil {
+			code = c
+		}
+	}
+
+	return newStatusError(code, e.Message, e.Extensions.Violations)
+}
+
+// do sends a GraphQL request and decodes the data of the response.
+func (c *graphqlClient) do(
+	ctx context.Context,
+	query string,
+	variables map[string]interface{},
+	data interface{},
+) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return requestError(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return requestError(err)
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []graphqlError  `json:"errors"`
+	}
+
+	err = json.Unmarshal(respBody, &result)
+	if err != nil || (resp.StatusCode >= http.StatusMultipleChoices && len(result.Errors) == 0) {
+		if resp.StatusCode >= http.StatusMultipleChoices {
+			return problemError(resp.StatusCode, respBody)
+		}
+
+		return status.Errorf(codes.Internal, "invalid GraphQL response: %s", err)
+	}
+
+	if len(result.Errors) > 0 {
+		return result.Errors[0].err()
+	}
+
+	return json.Unmarshal(result.Data, data)
+}
+
+func (c *graphqlClient) AddItem(
+	ctx context.Context,
+	in *todov1.AddItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.AddItemResponse, error) {
+	query := "mutation($input: NewTodoItem!) { addTodoItem(input: $input) { " + graphqlTodoItemFields + " } }"
+
+	variables := map[string]interface{}{
+		"input": map[string]interface{}{
+			"title": in.GetTitle(),
+			"order": in.GetOrder(),
+		},
+	}
+
+	var data struct {
+		AddTodoItem graphqlTodoItem `json:"addTodoItem"`
+	}
+
+	err := c.do(ctx, query, variables, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &todov1.AddItemResponse{Item: data.AddTodoItem.proto()}, nil
+}
+
+func (c *graphqlClient) ListItems(
+	ctx context.Context,
+	_ *todov1.ListItemsRequest,
+	_ ...grpc.CallOption,
+) (*todov1.ListItemsResponse, error) {
+	query := "query { todoItems { " + graphqlTodoItemFields + " } }"
+
+	var data struct {
+		TodoItems []graphqlTodoItem `json:"todoItems"`
+	}
+
+	err := c.do(ctx, query, nil, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*todov1.TodoItem, 0, len(data.TodoItems))
+
+	for _, item := range data.TodoItems {
+		items = append(items, item.proto())
+	}
+
+	return &todov1.ListItemsResponse{Items: items}, nil
+}
+
+func (c *graphqlClient) DeleteItems(
+	_ context.Context,
+	_ *todov1.DeleteItemsRequest,
+	_ ...grpc.CallOption,
+) (*todov1.DeleteItemsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "the GraphQL API cannot delete items")
+}
+
+func (c *graphqlClient) GetItem(
+	ctx context.Context,
+	in *todov1.GetItemRequest,
+	opts ...grpc.CallOption,
+) (*todov1.GetItemResponse, error) {
+	resp, err := c.ListItems(ctx, &todov1.ListItemsRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range resp.GetItems() {
+		if item.GetId() == in.GetId() {
+			return &todov1.GetItemResponse{Item: item}, nil
+		}
+	}
+
+	// Same message as the other APIs
+	return nil, status.Error(codes.NotFound, "get item: item not found")
+}
+
+func (c *graphqlClient) UpdateItem(
+	ctx context.Context,
+	in *todov1.UpdateItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.UpdateItemResponse, error) {
+	query := "mutation($input: TodoItemUpdate!) { updateTodoItem(input: $input) { " + graphqlTodoItemFields + " } }"
+
+	input := map[string]interface{}{
+		"id": in.GetId(),
+	}
+
+	if in.Title != nil {
+		input["title"] = in.Title.GetValue()
+	}
+
+	if in.Completed != nil {
+		input["completed"] = in.Completed.GetValue()
+	}
+
+	if in.Order != nil {
+		input["order"] = in.Order.GetValue()
+	}
+
+	var data struct {
+		UpdateTodoItem graphqlTodoItem `json:"updateTodoItem"`
+	}
+
+	err := c.do(ctx, query, map[string]interface{}{"input": input}, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &todov1.UpdateItemResponse{Item: data.UpdateTodoItem.proto()}, nil
+}
+
+func (c *graphqlClient) DeleteItem(
+	_ context.Context,
+	_ *todov1.DeleteItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.DeleteItemResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "the GraphQL API cannot delete items")
+}
@@ -0,0 +1,148 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+
+	"emperror.dev/errors"
+	"google.golang.org/grpc"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+	todorest "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1/client/rest"
+)
+
+type httpClient struct {
+	api *todorest.TodoListApiService
+}
+
+// NewHTTPClient returns a todo list client using the /todos HTTP API at a base URL (eg. http://localhost:8000).
+func NewHTTPClient(baseURL string, client *http.Client) todov1.TodoListServiceClient {
+	config := todorest.NewConfiguration()
+	config.Servers = todorest.ServerConfigurations{{URL: baseURL}}
+	config.HTTPClient = client
+	config.UserAgent = userAgent
+
+	return &httpClient{
+		api: todorest.NewAPIClient(config).TodoListApi,
+	}
+}
+
+// httpError converts an error returned by the HTTP API client to a status error.
+func httpError(resp *http.Response, err error) error {
+	var apiErr todorest.GenericOpenAPIError
+
+	if resp != nil && errors.As(err, &apiErr) && resp.StatusCode >= http.StatusMultipleChoices {
+		return problemError(resp.StatusCode, apiErr.Body())
+	}
+
+	return requestError(err)
+}
+
+func todoItemFromHTTP(item todorest.TodoItem) *todov1.TodoItem {
+	return &todov1.TodoItem{
+		Id:        item.GetId(),
+		Title:     item.GetTitle(),
+		Completed: item.GetCompleted(),
+		Order:     item.GetOrder(),
+	}
+}
+
+func (c *httpClient) AddItem(
+	ctx context.Context,
+	in *todov1.AddItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.AddItemResponse, error) {
+	req := todorest.NewAddTodoItemRequest(in.GetTitle(), in.GetOrder())
+
+	item, resp, err := c.api.AddItem(ctx).AddTodoItemRequest(*req).Execute()
+	if err != nil {
+		return nil, httpError(resp, err)
+	}
+
+	return &todov1.AddItemResponse{Item: todoItemFromHTTP(item)}, nil
+}
+
+func (c *httpClient) ListItems(
+	ctx context.Context,
+	_ *todov1.ListItemsRequest,
+	_ ...grpc.CallOption,
+) (*todov1.ListItemsResponse, error) {
+	items, resp, err := c.api.ListItems(ctx).Execute()
+	if err != nil {
+		return nil, httpError(resp, err)
+	}
+
+	result := make([]*todov1.TodoItem, 0, len(items))
+
+	for _, item := range items {
+		result = append(result, todoItemFromHTTP(item))
+	}
+
+	return &todov1.ListItemsResponse{Items: result}, nil
+}
+
+func (c *httpClient) DeleteItems(
+	ctx context.Context,
+	_ *todov1.DeleteItemsRequest,
+	_ ...grpc.CallOption,
+) (*todov1.DeleteItemsResponse, error) {
+	resp, err := c.api.DeleteItems(ctx).Execute()
+	if err != nil {
+		return nil, httpError(resp, err)
+	}
+
+	return &todov1.DeleteItemsResponse{}, nil
+}
+
+func (c *httpClient) GetItem(
+	ctx context.Context,
+	in *todov1.GetItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.GetItemResponse, error) {
+	item, resp, err := c.api.GetItem(ctx, in.GetId()).Execute()
+	if err != nil {
+		return nil, httpError(resp, err)
+	}
+
+	return &todov1.GetItemResponse{Item: todoItemFromHTTP(item)}, nil
+}
+
+func (c *httpClient) UpdateItem(
+	ctx context.Context,
+	in *todov1.UpdateItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.UpdateItemResponse, error) {
+	req := todorest.NewUpdateTodoItemRequest()
+
+	if in.Title != nil {
+		req.SetTitle(in.Title.GetValue())
+	}
+
+	if in.Completed != nil {
+		req.SetCompleted(in.Completed.GetValue())
+	}
+
+	if in.Order != nil {
+		req.SetOrder(in.Order.GetValue())
+	}
+
+	item, resp, err := c.api.UpdateItem(ctx, in.GetId()).UpdateTodoItemRequest(*req).Execute()
+	if err != nil {
+		return nil, httpError(resp, err)
+	}
+
+	return &todov1.UpdateItemResponse{Item: todoItemFromHTTP(item)}, nil
+}
+
+func (c *httpClient) DeleteItem(
+	ctx context.Context,
+	in *todov1.DeleteItemRequest,
+	_ ...grpc.CallOption,
+) (*todov1.DeleteItemResponse, error) {
+	resp, err := c.api.DeleteItem(ctx, in.GetId()).Execute()
+	if err != nil {
+		return nil, httpError(resp, err)
+	}
+
+	return &todov1.DeleteItemResponse{}, nil
+}
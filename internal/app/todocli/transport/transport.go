@@ -0,0 +1,139 @@
+// Package transport provides todo list clients using the HTTP and GraphQL APIs of the service.
+//
+// The clients implement the interface of the gRPC client (returning gRPC status errors),
+// so that commands work the same way regardless of the transport.
+package transport
+
+import (
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+const userAgent = "todocli"
+
+type tokenRoundTripper struct {
+	base  http.RoundTripper
+	token string
+}
+
+// WithToken sends a bearer token with every request.
+func WithToken(base http.RoundTripper, token string) http.RoundTripper {
+	return tokenRoundTripper{
+		base:  base,
+		token: token,
+	}
+}
+
+func (t tokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+
+	return t.base.RoundTrip(req)
+}
+
+type retryRoundTripper struct {
+	base    http.RoundTripper
+	retries int
+}
+
+// WithRetries retries requests failing because the service is unavailable
+// (the connection fails or a proxy responds with 502, 503 or 504).
+//
+// Requests that are not idempotent (eg. adding an item) are only retried when the connection fails,
+// since the service may have processed them otherwise.
+// Requests with a body that cannot be sent again are not retried.
+func WithRetries(base http.RoundTripper, retries int) http.RoundTripper {
+	return retryRoundTripper{
+		base:    base,
+		retries: retries,
+	}
+}
+
+func (t retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	backoff := 100 * time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(ctx)
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if attempt == t.retries || !retryable(req, resp, err) || ctx.Err() != nil {
+			return resp, err
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			// The last error is more useful than the context error
+			return resp, err
+
+		case <-timer.C:
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		backoff *= 2
+		if backoff > time.Second {
+			backoff = time.Second
+		}
+	}
+}
+
+func retryable(req *http.Request, resp *http.Response, err error) bool {
+	// The body cannot be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	// The request has not been sent
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	if !idempotent(req.Method) {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// idempotent tells whether sending a request with the method more than once has the same effect as sending it once.
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+
+	default:
+		return false
+	}
+}
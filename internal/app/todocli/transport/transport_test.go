@@ -0,0 +1,149 @@
+package transport
+
+import (
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRetries(t *testing.T) {
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: WithRetries(WithToken(http.DefaultTransport, "secret"), 2)}
+
+	req, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("request"))
+	require.NoError(t, err)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// The body is sent again with every attempt
+	assert.Equal(t, []string{"request", "request", "request"}, bodies)
+}
+
+func TestWithRetries_GiveUp(t *testing.T) {
+	var attempts int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: WithRetries(http.DefaultTransport, 1)}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, 2, attempts)
+}
+
+func TestWithRetries_NotIdempotent(t *testing.T) {
+	var attempts int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: WithRetries(http.DefaultTransport, 2)}
+
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("request"))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The service may have processed the request
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 1, attempts)
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestWithRetries_Errors(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+
+	tests := map[string]struct {
+		method   string
+		err      error
+		attempts int
+	}{
+		"dialNotIdempotent": {http.MethodPost, dialErr, 3},
+		"readNotIdempotent": {http.MethodPost, readErr, 1},
+		"readIdempotent":    {http.MethodGet, readErr, 3},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			var attempts int
+
+			transport := WithRetries(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+
+				return nil, test.err
+			}), 2)
+
+			req, err := http.NewRequest(test.method, "http://127.0.0.1", strings.NewReader("request"))
+			require.NoError(t, err)
+
+			_, err = transport.RoundTrip(req)
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.attempts, attempts)
+		})
+	}
+}
+
+func TestWithRetries_BodyNotRewindable(t *testing.T) {
+	var attempts int
+
+	transport := WithRetries(roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}), 2)
+
+	req, err := http.NewRequest(http.MethodPut, "http://127.0.0.1", strings.NewReader("request"))
+	require.NoError(t, err)
+
+	req.GetBody = nil
+
+	resp, err := transport.RoundTrip(req)
+	assert.Nil(t, resp)
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,115 @@
+package tui
+
+import (
+	"unicode/utf8"
+)
+
+type keyType int
+
+const (
+	keyRune keyType = iota
+	keyUp
+	keyDown
+	keyHome
+	keyEnd
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyBackspace
+	keyEscape
+	keyCtrlC
+	keyCtrlU
+)
+
+// key is a key pressed by the user.
+type key struct {
+	typ keyType
+	r   rune
+}
+
+// escapeSequences are the escape sequences of special keys sent by common terminals.
+// nolint: gochecknoglobals
+var escapeSequences = map[string]keyType{
+	"[A":  keyUp,
+	"OA":  keyUp,
+	"[B":  keyDown,
+	"OB":  keyDown,
+	"[H":  keyHome,
+	"OH":  keyHome,
+	"[1~": keyHome,
+	"[F":  keyEnd,
+	"OF":  keyEnd,
+	"[4~": keyEnd,
+	"[5~": keyPageUp,
+	"[6~": keyPageDown,
+}
+
+// parseKeys parses the keys in a chunk of terminal input.
+// Unknown escape sequences are ignored.
+func parseKeys(input []byte) []key {
+	var keys []key
+
+	for len(input) > 0 {
+		switch b := input[0]; {
+		case b == 0x1b && len(input) == 1:
+			keys = append(keys, key{typ: keyEscape})
+			input = input[1:]
+
+		case b == 0x1b:
+			n := escapeSequenceLength(input[1:])
+			if typ, ok := escapeSequences[string(input[1:1+n])]; ok {
+				keys = append(keys, key{typ: typ})
+			}
+
+			input = input[1+n:]
+
+		case b == '\r' || b == '\n':
+			keys = append(keys, key{typ: keyEnter})
+			input = input[1:]
+
+		case b == 0x7f || b == 0x08:
+			keys = append(keys, key{typ: keyBackspace})
+			input = input[1:]
+
+		case b == 0x03:
+			keys = append(keys, key{typ: keyCtrlC})
+			input = input[1:]
+
+		case b == 0x15:
+			keys = append(keys, key{typ: keyCtrlU})
+			input = input[1:]
+
+		case b < 0x20:
+			// Other control characters
+			input = input[1:]
+
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, key{typ: keyRune, r: r})
+			input = input[size:]
+		}
+	}
+
+	return keys
+}
+
+// escapeSequenceLength returns the length of an escape sequence (following the escape character).
+func escapeSequenceLength(seq []byte) int {
+	if len(seq) < 2 || (seq[0] != '[' && seq[0] != 'O') {
+		return 0
+	}
+
+	// SS3 sequences are a single character
+	if seq[0] == 'O' {
+		return 2
+	}
+
+	// CSI sequences end with a byte in the 0x40–0x7e range
+	for i := 1; i < len(seq); i++ {
+		if seq[i] >= 0x40 && seq[i] <= 0x7e {
+			return i + 1
+		}
+	}
+
+	return len(seq)
+}
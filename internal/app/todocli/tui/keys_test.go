@@ -0,0 +1,51 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := map[string]struct {
+		input    string
+		expected []key
+	}{
+		"runes": {
+			input:    "aé",
+			expected: []key{{typ: keyRune, r: 'a'}, {typ: keyRune, r: 'é'}},
+		},
+		"arrows": {
+			input:    "\x1b[A\x1b[B\x1bOA",
+			expected: []key{{typ: keyUp}, {typ: keyDown}, {typ: keyUp}},
+		},
+		"homeEnd": {
+			input:    "\x1b[H\x1b[4~",
+			expected: []key{{typ: keyHome}, {typ: keyEnd}},
+		},
+		"pages": {
+			input:    "\x1b[5~\x1b[6~",
+			expected: []key{{typ: keyPageUp}, {typ: keyPageDown}},
+		},
+		"escape": {
+			input:    "\x1b",
+			expected: []key{{typ: keyEscape}},
+		},
+		"controls": {
+			input:    "\r\x7f\x03\x15",
+			expected: []key{{typ: keyEnter}, {typ: keyBackspace}, {typ: keyCtrlC}, {typ: keyCtrlU}},
+		},
+		"unknownSequence": {
+			input:    "\x1b[1;5Cx",
+			expected: []key{{typ: keyRune, r: 'x'}},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.expected, parseKeys([]byte(test.input)))
+		})
+	}
+}
@@ -0,0 +1,405 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+)
+
+// item is a todo item shown in the list.
+type item struct {
+	id        string
+	title     string
+	completed bool
+	order     int32
+}
+
+type mode int
+
+const (
+	modeList mode = iota
+	modeAdd
+	modeEdit
+	modeFilter
+	modeConfirmDelete
+)
+
+// action is a change requested by the user.
+type action interface{}
+
+type (
+	quitAction    struct{}
+	refreshAction struct{}
+
+	addAction struct {
+		title string
+		order int32
+	}
+
+	editAction struct {
+		id    string
+		title string
+	}
+
+	toggleAction struct {
+		id        string
+		completed bool
+	}
+
+	deleteAction struct {
+		id string
+	}
+
+	// reorderAction changes the order of items (item ID to new order).
+	reorderAction struct {
+		orders map[string]int32
+	}
+)
+
+// model is the state of the user interface.
+// It turns keys into actions, but does not execute them.
+type model struct {
+	items  []item
+	cursor int // index of the selected item in the visible list
+	offset int // index of the first visible item on screen
+	filter string
+
+	mode  mode
+	input []rune
+
+	status string
+	height int // number of list rows fitting on screen
+}
+
+// setItems replaces the items, keeping the selected item selected (if it still exists).
+func (m *model) setItems(items []item) {
+	selected, hasSelected := m.selected()
+
+	m.items = append([]item(nil), items...)
+
+	sort.SliceStable(m.items, func(i, j int) bool {
+		if m.items[i].order != m.items[j].order {
+			return m.items[i].order < m.items[j].order
+		}
+
+		// ULIDs sort by creation time
+		return m.items[i].id < m.items[j].id
+	})
+
+	if hasSelected {
+		m.selectItem(selected.id)
+	}
+
+	m.clampCursor()
+}
+
+// setFilter changes the filter, keeping the selected item selected (if it matches the filter).
+func (m *model) setFilter(filter string) {
+	selected, hasSelected := m.selected()
+
+	m.filter = filter
+	m.cursor = 0
+
+	if hasSelected {
+		m.selectItem(selected.id)
+	}
+
+	m.clampCursor()
+}
+
+func (m *model) selectItem(id string) {
+	if i := indexOf(m.visible(), id); i >= 0 {
+		m.cursor = i
+	}
+}
+
+// visible returns the items matching the filter.
+func (m *model) visible() []item {
+	if m.filter == "" {
+		return m.items
+	}
+
+	filter := strings.ToLower(m.filter)
+
+	var items []item
+
+	for _, item := range m.items {
+		if strings.Contains(strings.ToLower(item.title), filter) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func (m *model) selected() (item, bool) {
+	items := m.visible()
+	if m.cursor < 0 || m.cursor >= len(items) {
+		return item{}, false
+	}
+
+	return items[m.cursor], true
+}
+
+func (m *model) clampCursor() {
+	n := len(m.visible())
+
+	if m.cursor >= n {
+		m.cursor = n - 1
+	}
+
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+
+	if m.height > 0 {
+		if m.cursor < m.offset {
+			m.offset = m.cursor
+		}
+
+		if m.cursor >= m.offset+m.height {
+			m.offset = m.cursor - m.height + 1
+		}
+	}
+
+	if m.offset > m.cursor {
+		m.offset = m.cursor
+	}
+}
+
+// handleKey updates the model with a key pressed by the user and returns the requested action (if any).
+func (m *model) handleKey(k key) action {
+	if k.typ == keyCtrlC {
+		return quitAction{}
+	}
+
+	switch m.mode {
+	case modeList:
+		return m.handleListKey(k)
+
+	case modeConfirmDelete:
+		m.mode = modeList
+
+		selected, ok := m.selected()
+		if ok && k.typ == keyRune && (k.r == 'y' || k.r == 'Y') {
+			return deleteAction{id: selected.id}
+		}
+
+		m.status = "Deletion cancelled."
+
+		return nil
+
+	default:
+		return m.handleInputKey(k)
+	}
+}
+
+func (m *model) handleListKey(k key) action {
+	m.status = ""
+
+	switch k.typ {
+	case keyUp:
+		m.cursor--
+
+	case keyDown:
+		m.cursor++
+
+	case keyHome:
+		m.cursor = 0
+
+	case keyEnd:
+		m.cursor = len(m.visible()) - 1
+
+	case keyPageUp:
+		m.cursor -= m.height
+
+	case keyPageDown:
+		m.cursor += m.height
+
+	case keyEscape:
+		m.setFilter("")
+
+	case keyEnter:
+		return m.toggle()
+
+	case keyRune:
+		return m.handleListRune(k.r)
+	}
+
+	m.clampCursor()
+
+	return nil
+}
+
+func (m *model) handleListRune(r rune) action {
+	switch r {
+	case 'q':
+		return quitAction{}
+
+	case 'k':
+		m.cursor--
+
+	case 'j':
+		m.cursor++
+
+	case 'g':
+		m.cursor = 0
+
+	case 'G':
+		m.cursor = len(m.visible()) - 1
+
+	case ' ', 'x':
+		return m.toggle()
+
+	case 'a':
+		m.mode = modeAdd
+		m.input = nil
+
+	case 'e':
+		if selected, ok := m.selected(); ok {
+			m.mode = modeEdit
+			m.input = []rune(selected.title)
+		}
+
+	case 'd':
+		if _, ok := m.selected(); ok {
+			m.mode = modeConfirmDelete
+		}
+
+	case '/':
+		m.mode = modeFilter
+		m.input = []rune(m.filter)
+
+	case 'K':
+		return m.move(-1)
+
+	case 'J':
+		return m.move(1)
+
+	case 'r':
+		return refreshAction{}
+	}
+
+	m.clampCursor()
+
+	return nil
+}
+
+func (m *model) handleInputKey(k key) action {
+	switch k.typ {
+	case keyEscape:
+		// The list is filtered while typing, so cancelling the filter prompt clears the filter
+		if m.mode == modeFilter {
+			m.setFilter("")
+		}
+
+		m.mode = modeList
+		m.input = nil
+
+		return nil
+
+	case keyBackspace:
+		if len(m.input) > 0 {
+			m.input = m.input[:len(m.input)-1]
+		}
+
+	case keyCtrlU:
+		m.input = nil
+
+	case keyRune:
+		m.input = append(m.input, k.r)
+
+	case keyEnter:
+		return m.submit()
+	}
+
+	if m.mode == modeFilter {
+		m.setFilter(string(m.input))
+	}
+
+	return nil
+}
+
+func (m *model) submit() action {
+	mode := m.mode
+	input := strings.TrimSpace(string(m.input))
+
+	m.mode = modeList
+	m.input = nil
+
+	switch mode {
+	case modeAdd:
+		if input == "" {
+			return nil
+		}
+
+		var order int32
+		if len(m.items) > 0 {
+			order = m.items[len(m.items)-1].order + 1
+		}
+
+		return addAction{title: input, order: order}
+
+	case modeEdit:
+		selected, ok := m.selected()
+		if !ok || input == "" || input == selected.title {
+			return nil
+		}
+
+		return editAction{id: selected.id, title: input}
+
+	case modeFilter:
+		m.setFilter(input)
+	}
+
+	return nil
+}
+
+func (m *model) toggle() action {
+	selected, ok := m.selected()
+	if !ok {
+		return nil
+	}
+
+	return toggleAction{id: selected.id, completed: !selected.completed}
+}
+
+// move moves the selected item up or down the list.
+// Items are renumbered, so that items with the same order can be reordered too.
+func (m *model) move(delta int) action {
+	visible := m.visible()
+
+	target := m.cursor + delta
+	if target < 0 || target >= len(visible) {
+		return nil
+	}
+
+	// Swap the items in the full list (they may not be next to each other when filtering)
+	items := append([]item(nil), m.items...)
+
+	i, j := indexOf(items, visible[m.cursor].id), indexOf(items, visible[target].id)
+	items[i], items[j] = items[j], items[i]
+
+	orders := make(map[string]int32)
+
+	for index, item := range items {
+		if order := int32(index + 1); item.order != order {
+			orders[item.id] = order
+			items[index].order = order
+		}
+	}
+
+	// The moved item stays selected
+	m.setItems(items)
+
+	return reorderAction{orders: orders}
+}
+
+func indexOf(items []item, id string) int {
+	for i, item := range items {
+		if item.id == id {
+			return i
+		}
+	}
+
+	return -1
+}
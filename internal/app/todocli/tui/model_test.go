@@ -0,0 +1,189 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestModel() *model {
+	m := &model{height: 10}
+
+	m.setItems([]item{
+		{id: "03", title: "Walk the dog", order: 2},
+		{id: "01", title: "Buy milk", order: 1},
+		{id: "02", title: "Buy bread", order: 1, completed: true},
+	})
+
+	return m
+}
+
+func typeKeys(m *model, input string) action {
+	var a action
+
+	for _, k := range parseKeys([]byte(input)) {
+		a = m.handleKey(k)
+	}
+
+	return a
+}
+
+func titles(items []item) []string {
+	var titles []string
+
+	for _, item := range items {
+		titles = append(titles, item.title)
+	}
+
+	return titles
+}
+
+func TestModel_SetItems(t *testing.T) {
+	m := newTestModel()
+
+	assert.Equal(t, []string{"Buy milk", "Buy bread", "Walk the dog"}, titles(m.visible()))
+
+	typeKeys(m, "j")
+
+	// The selected item stays selected
+	m.setItems(append(m.items, item{id: "00", title: "First", order: 0}))
+
+	selected, _ := m.selected()
+	assert.Equal(t, "Buy bread", selected.title)
+
+	// The cursor stays in range when items disappear
+	m.cursor = 3
+	m.setItems(m.items[:1])
+
+	assert.Equal(t, 0, m.cursor)
+}
+
+func TestModel_Navigate(t *testing.T) {
+	m := newTestModel()
+
+	typeKeys(m, "jj")
+	assert.Equal(t, 2, m.cursor)
+
+	typeKeys(m, "j")
+	assert.Equal(t, 2, m.cursor)
+
+	typeKeys(m, "\x1b[A")
+	assert.Equal(t, 1, m.cursor)
+
+	typeKeys(m, "g")
+	assert.Equal(t, 0, m.cursor)
+
+	typeKeys(m, "G")
+	assert.Equal(t, 2, m.cursor)
+}
+
+func TestModel_Scroll(t *testing.T) {
+	m := newTestModel()
+	m.height = 2
+
+	typeKeys(m, "jj")
+
+	assert.Equal(t, 1, m.offset)
+
+	typeKeys(m, "g")
+
+	assert.Equal(t, 0, m.offset)
+}
+
+func TestModel_Toggle(t *testing.T) {
+	m := newTestModel()
+
+	assert.Equal(t, toggleAction{id: "01", completed: true}, typeKeys(m, " "))
+	assert.Equal(t, toggleAction{id: "02", completed: false}, typeKeys(m, "jx"))
+}
+
+func TestModel_Add(t *testing.T) {
+	m := newTestModel()
+
+	assert.Equal(t, addAction{title: "Read a book", order: 3}, typeKeys(m, "aRead a boox\x7fk\r"))
+	assert.Equal(t, modeList, m.mode)
+
+	// Empty titles are ignored
+	assert.Nil(t, typeKeys(m, "a  \r"))
+
+	// Escape cancels
+	assert.Nil(t, typeKeys(m, "aSomething\x1b"))
+	assert.Equal(t, modeList, m.mode)
+}
+
+func TestModel_Edit(t *testing.T) {
+	m := newTestModel()
+
+	assert.Equal(t, editAction{id: "01", title: "Buy oat milk"}, typeKeys(m, "e\x15Buy oat milk\r"))
+
+	// Unchanged titles are ignored
+	assert.Nil(t, typeKeys(m, "e\r"))
+}
+
+func TestModel_Delete(t *testing.T) {
+	m := newTestModel()
+
+	assert.Nil(t, typeKeys(m, "dn"))
+	assert.Equal(t, "Deletion cancelled.", m.status)
+
+	assert.Equal(t, deleteAction{id: "01"}, typeKeys(m, "dy"))
+}
+
+func TestModel_Filter(t *testing.T) {
+	m := newTestModel()
+
+	typeKeys(m, "/BUY\r")
+
+	assert.Equal(t, "BUY", m.filter)
+	assert.Equal(t, []string{"Buy milk", "Buy bread"}, titles(m.visible()))
+
+	assert.Equal(t, toggleAction{id: "02", completed: false}, typeKeys(m, "j "))
+
+	typeKeys(m, "\x1b")
+
+	assert.Empty(t, m.filter)
+	assert.Len(t, m.visible(), 3)
+
+	// The selected item stays selected when the filter changes
+	selected, _ := m.selected()
+	assert.Equal(t, "Buy bread", selected.title)
+
+	typeKeys(m, "/dog")
+
+	selected, _ = m.selected()
+	assert.Equal(t, "Walk the dog", selected.title)
+
+	// Cancelling the filter prompt clears the filter
+	typeKeys(m, "\x1b")
+
+	assert.Empty(t, m.filter)
+	assert.Equal(t, modeList, m.mode)
+}
+
+func TestModel_Reorder(t *testing.T) {
+	m := newTestModel()
+
+	// Items with the same order are renumbered
+	a := typeKeys(m, "J")
+
+	assert.Equal(t, reorderAction{orders: map[string]int32{"01": 2, "03": 3}}, a)
+	assert.Equal(t, []string{"Buy bread", "Buy milk", "Walk the dog"}, titles(m.visible()))
+
+	selected, _ := m.selected()
+	assert.Equal(t, "Buy milk", selected.title)
+
+	// Only changed orders are updated
+	a = typeKeys(m, "K")
+
+	assert.Equal(t, reorderAction{orders: map[string]int32{"01": 1, "02": 2}}, a)
+
+	// The first item cannot be moved up
+	assert.Nil(t, typeKeys(m, "K"))
+}
+
+func TestModel_Quit(t *testing.T) {
+	assert.Equal(t, quitAction{}, typeKeys(newTestModel(), "q"))
+
+	// Ctrl+C quits even when typing
+	assert.Equal(t, quitAction{}, typeKeys(newTestModel(), "a\x03"))
+}
@@ -0,0 +1,88 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package tui
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"emperror.dev/errors"
+	"golang.org/x/sys/unix"
+)
+
+// terminal is a terminal in raw mode.
+type terminal struct {
+	fd       int
+	original unix.Termios
+}
+
+// openTerminal puts a terminal into raw mode (no echo, no line buffering, no signals).
+func openTerminal(in *os.File) (*terminal, error) {
+	fd := int(in.Fd())
+
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, errors.New("the terminal UI requires an interactive terminal")
+	}
+
+	t := &terminal{
+		fd:       fd,
+		original: *termios,
+	}
+
+	raw := *termios
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, errors.WrapIf(err, "failed to put terminal into raw mode")
+	}
+
+	return t, nil
+}
+
+// size returns the size of the terminal.
+func (t *terminal) size() (width int, height int, err error) {
+	ws, err := unix.IoctlGetWinsize(t.fd, unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, errors.WrapIf(err, "failed to get terminal size")
+	}
+
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// waitForInput waits for input to become available for reading (up to the timeout).
+func (t *terminal) waitForInput(timeout time.Duration) (bool, error) {
+	fds := []unix.PollFd{{Fd: int32(t.fd), Events: unix.POLLIN}}
+
+	n, err := unix.Poll(fds, int(timeout/time.Millisecond))
+	if err == unix.EINTR {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, errors.WrapIf(err, "failed to wait for input")
+	}
+
+	return n > 0, nil
+}
+
+// restore restores the original terminal mode.
+func (t *terminal) restore() error {
+	return unix.IoctlSetTermios(t.fd, ioctlSetTermios, &t.original)
+}
+
+// notifyResize sends a value on the channel whenever the terminal is resized.
+func notifyResize(c chan<- os.Signal) func() {
+	signal.Notify(c, syscall.SIGWINCH)
+
+	return func() { signal.Stop(c) }
+}
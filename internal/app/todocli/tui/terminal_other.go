@@ -0,0 +1,34 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package tui
+
+import (
+	"os"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+type terminal struct{}
+
+// openTerminal fails: the terminal UI is not supported on this platform.
+func openTerminal(_ *os.File) (*terminal, error) {
+	return nil, errors.New("the terminal UI is not supported on this platform")
+}
+
+func (t *terminal) size() (width int, height int, err error) {
+	return 0, 0, nil
+}
+
+func (t *terminal) waitForInput(_ time.Duration) (bool, error) {
+	return false, errors.New("the terminal UI is not supported on this platform")
+}
+
+func (t *terminal) restore() error {
+	return nil
+}
+
+func notifyResize(_ chan<- os.Signal) func() {
+	return func() {}
+}
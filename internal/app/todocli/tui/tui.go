@@ -0,0 +1,281 @@
+// Package tui implements an interactive terminal user interface for managing todo items.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"google.golang.org/grpc/status"
+
+	todov1 "github.com/sagikazarmark/todobackend-go-kit/api/todo/v1"
+)
+
+// Config configures the terminal user interface.
+type Config struct {
+	// Client is the client of the todo service.
+	Client todov1.TodoListServiceClient
+
+	// Timeout of requests.
+	Timeout time.Duration
+
+	// PollInterval is the time between refreshes of the list.
+	PollInterval time.Duration
+}
+
+// Watcher is implemented by clients notified about changes of the todo list (eg. through a server stream).
+type Watcher interface {
+	// Watch returns a channel receiving a value whenever the todo list changes.
+	// The channel is closed when watching stops (eg. the context is canceled or the stream fails).
+	Watch(ctx context.Context) (<-chan struct{}, error)
+}
+
+// keyPollTimeout is the time between checking for input and whether the user interface stopped.
+const keyPollTimeout = 100 * time.Millisecond
+
+// Run runs the terminal user interface until the user quits.
+//
+// The list is refreshed when the client notifies about changes (if it implements Watcher).
+// The todo service does not notify clients about changes yet, so the list is refreshed by polling otherwise
+// (and when watching stops).
+func Run(ctx context.Context, config Config, in *os.File, out io.Writer) error {
+	term, err := openTerminal(in)
+	if err != nil {
+		return err
+	}
+	defer term.restore() // nolint: errcheck
+
+	fmt.Fprint(out, enterAltBuf+hideCursor+clearScreen)
+	defer fmt.Fprint(out, showCursor+leaveAltBuf)
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	keys := make(chan []key)
+	keysDone := make(chan struct{})
+
+	go func() {
+		defer close(keysDone)
+
+		readKeys(ctx, in, term.waitForInput, keys)
+	}()
+
+	// Stop reading keys before restoring the terminal
+	defer func() {
+		cancel()
+		<-keysDone
+	}()
+
+	resize := make(chan os.Signal, 1)
+	defer notifyResize(resize)()
+
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+
+	poll := ticker.C
+
+	var changes <-chan struct{}
+
+	if watcher, ok := config.Client.(Watcher); ok {
+		changes, err = watcher.Watch(ctx)
+		if err == nil {
+			poll = nil
+		}
+	}
+
+	ui := &ui{
+		client:  config.Client,
+		timeout: config.Timeout,
+		model:   &model{},
+	}
+
+	ui.refresh(ctx)
+
+	for {
+		width, height, err := term.size()
+		if err != nil {
+			return err
+		}
+
+		ui.model.height = height - headerLines - footerLines
+		ui.model.clampCursor()
+
+		fmt.Fprint(out, render(ui.model, width, height))
+
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case ks, ok := <-keys:
+			if !ok {
+				return nil
+			}
+
+			for _, k := range ks {
+				a := ui.model.handleKey(k)
+				if _, ok := a.(quitAction); ok {
+					return nil
+				}
+
+				if a != nil {
+					ui.execute(ctx, a)
+				}
+			}
+
+		case _, ok := <-changes:
+			if !ok {
+				// Fall back to polling
+				changes = nil
+				poll = ticker.C
+			}
+
+			ui.refresh(ctx)
+
+		case <-poll:
+			ui.refresh(ctx)
+
+		case <-resize:
+		}
+	}
+}
+
+// readKeys reads keys until reading fails or the context is canceled.
+// Input is only read when it is available, so that reading does not block after the context is canceled.
+func readKeys(ctx context.Context, in io.Reader, wait func(timeout time.Duration) (bool, error), keys chan<- []key) {
+	defer close(keys)
+
+	buf := make([]byte, 256)
+
+	for ctx.Err() == nil {
+		ready, err := wait(keyPollTimeout)
+		if err != nil {
+			return
+		}
+
+		if !ready {
+			continue
+		}
+
+		n, err := in.Read(buf)
+		if err != nil {
+			return
+		}
+
+		select {
+		case keys <- parseKeys(buf[:n]):
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ui executes actions against the todo service.
+type ui struct {
+	client  todov1.TodoListServiceClient
+	timeout time.Duration
+	model   *model
+}
+
+func (u *ui) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+
+	resp, err := u.client.ListItems(ctx, &todov1.ListItemsRequest{})
+	if err != nil {
+		u.model.status = errorMessage(err)
+
+		return
+	}
+
+	items := make([]item, 0, len(resp.GetItems()))
+
+	for _, i := range resp.GetItems() {
+		items = append(items, item{
+			id:        i.GetId(),
+			title:     i.GetTitle(),
+			completed: i.GetCompleted(),
+			order:     i.GetOrder(),
+		})
+	}
+
+	u.model.setItems(items)
+}
+
+func (u *ui) execute(ctx context.Context, a action) {
+	message, err := u.do(ctx, a)
+	if err != nil {
+		u.model.status = errorMessage(err)
+	} else {
+		u.model.status = message
+	}
+
+	u.refresh(ctx)
+
+	// Keep errors of the action visible
+	if err != nil {
+		u.model.status = errorMessage(err)
+	}
+}
+
+func (u *ui) do(ctx context.Context, a action) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.timeout)
+	defer cancel()
+
+	switch a := a.(type) {
+	case refreshAction:
+		return "", nil
+
+	case addAction:
+		_, err := u.client.AddItem(ctx, &todov1.AddItemRequest{Title: a.title, Order: a.order})
+
+		return fmt.Sprintf("Added %q.", a.title), err
+
+	case editAction:
+		_, err := u.client.UpdateItem(ctx, &todov1.UpdateItemRequest{
+			Id:    a.id,
+			Title: &wrappers.StringValue{Value: a.title},
+		})
+
+		return "Item updated.", err
+
+	case toggleAction:
+		_, err := u.client.UpdateItem(ctx, &todov1.UpdateItemRequest{
+			Id:        a.id,
+			Completed: &wrappers.BoolValue{Value: a.completed},
+		})
+
+		if a.completed {
+			return "Item marked as complete.", err
+		}
+
+		return "Item reopened.", err
+
+	case deleteAction:
+		_, err := u.client.DeleteItem(ctx, &todov1.DeleteItemRequest{Id: a.id})
+
+		return "Item deleted.", err
+
+	case reorderAction:
+		for id, order := range a.orders {
+			_, err := u.client.UpdateItem(ctx, &todov1.UpdateItemRequest{
+				Id:    id,
+				Order: &wrappers.Int32Value{Value: order},
+			})
+			if err != nil {
+				return "", err
+			}
+		}
+
+		return "", nil
+
+	default:
+		return "", nil
+	}
+}
+
+func errorMessage(err error) string {
+	return "Error: " + status.Convert(err).Message()
+}
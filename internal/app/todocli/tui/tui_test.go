@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadKeys(t *testing.T) {
+	keys := make(chan []key)
+
+	ready := func(_ time.Duration) (bool, error) { return true, nil }
+
+	go readKeys(context.Background(), strings.NewReader("q"), ready, keys)
+
+	assert.Equal(t, []key{{r: 'q'}}, <-keys)
+
+	_, ok := <-keys
+	assert.False(t, ok, "keys should be closed when reading fails")
+}
+
+func TestReadKeys_Canceled(t *testing.T) {
+	tests := map[string]func(timeout time.Duration) (bool, error){
+		"waitingForInput": func(timeout time.Duration) (bool, error) {
+			time.Sleep(timeout)
+
+			return false, nil
+		},
+		"sendingKeys": func(_ time.Duration) (bool, error) {
+			return true, nil
+		},
+	}
+
+	for name, wait := range tests {
+		name, wait := name, wait
+
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			keys := make(chan []key)
+			done := make(chan struct{})
+
+			go func() {
+				defer close(done)
+
+				readKeys(ctx, strings.NewReader(strings.Repeat("q", 1024)), wait, keys)
+			}()
+
+			// Nobody receives keys
+			time.Sleep(10 * time.Millisecond)
+
+			cancel()
+
+			select {
+			case <-done:
+
+			case <-time.After(time.Second):
+				t.Fatal("reading keys did not stop")
+			}
+		})
+	}
+}
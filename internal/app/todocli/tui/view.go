@@ -0,0 +1,134 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ANSI escape sequences
+const (
+	clearScreen   = "\x1b[H\x1b[2J"
+	clearLine     = "\x1b[K"
+	reverse       = "\x1b[7m"
+	dim           = "\x1b[2m"
+	bold          = "\x1b[1m"
+	reset         = "\x1b[0m"
+	hideCursor    = "\x1b[?25l"
+	showCursor    = "\x1b[?25h"
+	enterAltBuf   = "\x1b[?1049h"
+	leaveAltBuf   = "\x1b[?1049l"
+	cursorToStart = "\x1b[H"
+)
+
+// headerLines and footerLines are the number of lines around the list.
+const (
+	headerLines = 2
+	footerLines = 3
+)
+
+const help = "↑/↓ move  space toggle  a add  e edit  d delete  J/K reorder  / filter  r refresh  q quit"
+
+// render renders the model on a screen of the given size.
+func render(m *model, width int, height int) string {
+	var b strings.Builder
+
+	b.WriteString(cursorToStart)
+
+	line := func(s string) {
+		b.WriteString(s)
+		b.WriteString(clearLine)
+		b.WriteString("\r\n")
+	}
+
+	completed := 0
+	for _, item := range m.items {
+		if item.completed {
+			completed++
+		}
+	}
+
+	header := fmt.Sprintf("Todo items (%d, %d completed)", len(m.items), completed)
+	if m.filter != "" {
+		header += fmt.Sprintf("  filter: %q", m.filter)
+	}
+
+	line(bold + truncate(header, width) + reset)
+	line("")
+
+	visible := m.visible()
+	rows := height - headerLines - footerLines
+
+	for i := m.offset; i < m.offset+rows; i++ {
+		if i >= len(visible) {
+			if i == 0 {
+				line(dim + "No todo items." + reset)
+			} else {
+				line("")
+			}
+
+			continue
+		}
+
+		item := visible[i]
+
+		check := "[ ]"
+		if item.completed {
+			check = "[x]"
+		}
+
+		row := truncate(fmt.Sprintf(" %s %s", check, item.title), width)
+
+		switch {
+		case i == m.cursor:
+			line(reverse + row + strings.Repeat(" ", max(0, width-runewidth.StringWidth(row))) + reset)
+
+		case item.completed:
+			line(dim + row + reset)
+
+		default:
+			line(row)
+		}
+	}
+
+	line("")
+	line(truncate(prompt(m), width))
+	b.WriteString(dim + truncate(help, width) + reset + clearLine)
+
+	return b.String()
+}
+
+// prompt returns the prompt (or status) line.
+func prompt(m *model) string {
+	switch m.mode {
+	case modeAdd:
+		return "New item: " + string(m.input) + "█"
+
+	case modeEdit:
+		return "Title: " + string(m.input) + "█"
+
+	case modeFilter:
+		return "Filter: " + string(m.input) + "█"
+
+	case modeConfirmDelete:
+		selected, _ := m.selected()
+
+		return fmt.Sprintf("Delete %q? (y/N)", selected.title)
+
+	default:
+		return m.status
+	}
+}
+
+func truncate(s string, width int) string {
+	return runewidth.Truncate(s, width, "…")
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stretchr/testify/require"
 	"logur.dev/logur"
 	"logur.dev/logur/conformance"
 	"logur.dev/logur/logtesting"
+
+	"github.com/sagikazarmark/modern-go-application/internal/platform/redact"
+	"github.com/sagikazarmark/modern-go-application/internal/platform/redact/redacttest"
 )
 
 func TestLogger(t *testing.T) {
@@ -41,6 +45,29 @@ func TestContextAwareLogger(t *testing.T) {
 	t.Run("Conformance", suite.Run)
 }
 
+func TestRedactingLogger(t *testing.T) {
+	testLogger := &logur.TestLoggerFacade{}
+
+	redactor, err := redact.NewRedactor(redact.Config{})
+	require.NoError(t, err)
+
+	logger := NewContextAwareLogger(
+		WithRedactor(testLogger, redactor),
+		func(_ context.Context) map[string]interface{} {
+			return map[string]interface{}{
+				"authorization": "Bearer t0k3n",
+			}
+		},
+	)
+
+	logger.
+		WithFields(map[string]interface{}{"password": "s3cr3t"}).
+		WithContext(context.Background()).
+		Info("connecting to root:s3cr3t@tcp(localhost:3306)/app", map[string]interface{}{"dsn": "root:s3cr3t@/app"})
+
+	redacttest.AssertNoSecretsInEvents(t, testLogger.Events(), "s3cr3t", "t0k3n")
+}
+
 func testLoggerWithFields(t *testing.T) {
 	testLogger := &logur.TestLoggerFacade{}
 
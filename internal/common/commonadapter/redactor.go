@@ -0,0 +1,89 @@
+package commonadapter
+
+import (
+	"context"
+
+	"logur.dev/logur"
+)
+
+// Redactor masks sensitive information in log events.
+type Redactor interface {
+	// Redact masks sensitive information in a string.
+	Redact(s string) string
+
+	// RedactFields masks sensitive information in a set of fields.
+	RedactFields(fields map[string]interface{}) map[string]interface{}
+}
+
+// WithRedactor returns a logger that masks sensitive information in every log event.
+//
+// Fields attached to the logger later (eg. using Logger.WithFields or a context extractor)
+// are redacted as well, since they are only merged when the event is recorded.
+func WithRedactor(logger logur.LoggerFacade, redactor Redactor) logur.LoggerFacade {
+	return redactingLogger{
+		logger:   logger,
+		redactor: redactor,
+	}
+}
+
+type redactingLogger struct {
+	logger   logur.LoggerFacade
+	redactor Redactor
+}
+
+func (l redactingLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.logger.Trace(l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+func (l redactingLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.logger.Debug(l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+func (l redactingLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.logger.Info(l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+func (l redactingLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.logger.Warn(l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+func (l redactingLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.logger.Error(l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+func (l redactingLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.TraceContext(ctx, l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+func (l redactingLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.DebugContext(ctx, l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+func (l redactingLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.InfoContext(ctx, l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+func (l redactingLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.WarnContext(ctx, l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+func (l redactingLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.logger.ErrorContext(ctx, l.redactor.Redact(msg), l.redactFields(fields)...)
+}
+
+// LevelEnabled implements the logur.LevelEnabler interface.
+func (l redactingLogger) LevelEnabled(level logur.Level) bool {
+	if levelEnabler, ok := l.logger.(logur.LevelEnabler); ok {
+		return levelEnabler.LevelEnabled(level)
+	}
+
+	return true
+}
+
+func (l redactingLogger) redactFields(fields []map[string]interface{}) []map[string]interface{} {
+	if len(fields) == 0 {
+		return fields
+	}
+
+	return []map[string]interface{}{l.redactor.RedactFields(fields[0])}
+}
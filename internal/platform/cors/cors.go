@@ -0,0 +1,128 @@
+// Package cors provides a Cross-Origin Resource Sharing middleware that can be reconfigured at runtime.
+package cors
+
+import (
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/gorilla/handlers"
+)
+
+// Config configures Cross-Origin Resource Sharing.
+type Config struct {
+	// AllowedOrigins is the list of origins allowed to make cross-origin requests.
+	// "*" (or an empty list) allows any origin.
+	AllowedOrigins []string
+
+	// AllowedMethods is the list of methods allowed in cross-origin requests.
+	// An empty list allows simple methods only (GET, HEAD and POST).
+	AllowedMethods []string
+
+	// AllowedHeaders is the list of headers allowed in cross-origin requests.
+	// An empty list allows simple headers only.
+	AllowedHeaders []string
+
+	// ExposedHeaders is the list of response headers exposed to cross-origin requests.
+	ExposedHeaders []string
+
+	// AllowCredentials allows cross-origin requests to include credentials (eg. cookies).
+	AllowCredentials bool
+
+	// MaxAge is the time preflight responses can be cached for (up to 10 minutes).
+	MaxAge time.Duration
+}
+
+// Validate validates the configuration.
+func (c Config) Validate() error {
+	if c.AllowCredentials && allowsAnyOrigin(c.AllowedOrigins) {
+		return errors.New("cors credentials cannot be allowed for any origin (list allowed origins explicitly)")
+	}
+
+	if c.MaxAge < 0 {
+		return errors.New("cors max age must not be negative")
+	}
+
+	return nil
+}
+
+func allowsAnyOrigin(allowedOrigins []string) bool {
+	if len(allowedOrigins) == 0 {
+		return true
+	}
+
+	for _, allowedOrigin := range allowedOrigins {
+		if allowedOrigin == "*" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handler is a CORS middleware whose configuration can be changed at runtime.
+type Handler struct {
+	next           http.Handler
+	handler        atomic.Value
+	allowedOrigins atomic.Value // []string
+}
+
+// NewHandler returns a new Handler.
+func NewHandler(config Config, next http.Handler) *Handler {
+	h := &Handler{
+		next: next,
+	}
+
+	h.SetConfig(config)
+
+	return h
+}
+
+// SetConfig changes the CORS configuration.
+func (h *Handler) SetConfig(config Config) {
+	options := []handlers.CORSOption{
+		handlers.AllowedOrigins(config.AllowedOrigins),
+		handlers.ExposedHeaders(config.ExposedHeaders),
+		handlers.MaxAge(int(config.MaxAge.Seconds())),
+	}
+
+	// Simple methods and headers are allowed by default
+	if len(config.AllowedMethods) > 0 {
+		options = append(options, handlers.AllowedMethods(config.AllowedMethods))
+	}
+
+	if len(config.AllowedHeaders) > 0 {
+		options = append(options, handlers.AllowedHeaders(config.AllowedHeaders))
+	}
+
+	if config.AllowCredentials {
+		options = append(options, handlers.AllowCredentials())
+	}
+
+	cors := handlers.CORS(options...)
+
+	h.handler.Store(cors(h.next))
+	h.allowedOrigins.Store(config.AllowedOrigins)
+}
+
+// AllowsOrigin reports whether cross-origin requests are allowed from an origin.
+func (h *Handler) AllowsOrigin(origin string) bool {
+	allowedOrigins := h.allowedOrigins.Load().([]string)
+	if allowsAnyOrigin(allowedOrigins) {
+		return true
+	}
+
+	for _, allowedOrigin := range allowedOrigins {
+		if allowedOrigin == origin {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.Load().(http.Handler).ServeHTTP(w, r)
+}
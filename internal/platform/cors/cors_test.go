@@ -0,0 +1,127 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_SetConfig(t *testing.T) {
+	handler := NewHandler(Config{AllowedOrigins: []string{"https://a.example.com"}}, http.NotFoundHandler())
+
+	allowedOrigin := func(origin string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Origin", origin)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Header().Get("Access-Control-Allow-Origin")
+	}
+
+	assert.Equal(t, "https://a.example.com", allowedOrigin("https://a.example.com"))
+	assert.Empty(t, allowedOrigin("https://b.example.com"))
+
+	handler.SetConfig(Config{AllowedOrigins: []string{"https://b.example.com"}})
+
+	assert.Empty(t, allowedOrigin("https://a.example.com"))
+	assert.Equal(t, "https://b.example.com", allowedOrigin("https://b.example.com"))
+
+	assert.False(t, handler.AllowsOrigin("https://a.example.com"))
+	assert.True(t, handler.AllowsOrigin("https://b.example.com"))
+
+	handler.SetConfig(Config{AllowedOrigins: []string{"*"}})
+
+	assert.True(t, handler.AllowsOrigin("https://a.example.com"))
+}
+
+func TestHandler_Preflight(t *testing.T) {
+	handler := NewHandler(
+		Config{
+			AllowedOrigins:   []string{"https://a.example.com"},
+			AllowedMethods:   []string{http.MethodGet, http.MethodPatch},
+			AllowedHeaders:   []string{"content-type", "x-api-key"},
+			ExposedHeaders:   []string{"RateLimit-Limit"},
+			AllowCredentials: true,
+			MaxAge:           5 * time.Minute,
+		},
+		http.NotFoundHandler(),
+	)
+
+	preflight := func(method string, headers string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set("Origin", "https://a.example.com")
+		req.Header.Set("Access-Control-Request-Method", method)
+		req.Header.Set("Access-Control-Request-Headers", headers)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	rec := preflight(http.MethodPatch, "x-api-key")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://a.example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, http.MethodPatch, rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "X-Api-Key", rec.Header().Get("Access-Control-Allow-Headers"))
+	assert.Equal(t, "true", rec.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Equal(t, "300", rec.Header().Get("Access-Control-Max-Age"))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, preflight(http.MethodDelete, "").Code)
+	assert.Equal(t, http.StatusForbidden, preflight(http.MethodGet, "authorization").Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Origin", "https://a.example.com")
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "Ratelimit-Limit", rec.Header().Get("Access-Control-Expose-Headers"))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		config Config
+		valid  bool
+	}{
+		"default": {
+			config: Config{},
+			valid:  true,
+		},
+		"credentials": {
+			config: Config{AllowedOrigins: []string{"https://a.example.com"}, AllowCredentials: true},
+			valid:  true,
+		},
+		"credentialsForAnyOrigin": {
+			config: Config{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+			valid:  false,
+		},
+		"credentialsForEmptyOrigins": {
+			config: Config{AllowCredentials: true},
+			valid:  false,
+		},
+		"negativeMaxAge": {
+			config: Config{MaxAge: -time.Second},
+			valid:  false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.config.Validate()
+
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -1,15 +1,25 @@
 package database
 
 import (
+	"context"
 	"database/sql/driver"
+	"sync/atomic"
 
 	"contrib.go.opencensus.io/integrations/ocsql"
 	"emperror.dev/errors"
 	"github.com/go-sql-driver/mysql"
 )
 
+// Connector is a database connector whose password can be changed at runtime (eg. when a secret is rotated).
+// Connections opened after the change use the new password, open connections are not affected.
+type Connector struct {
+	driver.Connector
+
+	mysql *mysqlConnector
+}
+
 // NewConnector returns a new database connector for the application.
-func NewConnector(config Config) (driver.Connector, error) {
+func NewConnector(config Config) (*Connector, error) {
 	// Set some mandatory parameters
 	config.Params["parseTime"] = "true"
 	config.Params["rejectReadOnly"] = "true"
@@ -20,22 +30,59 @@ func NewConnector(config Config) (driver.Connector, error) {
 		return nil, errors.WithStack(err)
 	}
 
-	connector, err := mysql.NewConnector(conf)
+	connector := &mysqlConnector{config: conf}
+
+	if err := connector.setPassword(config.Pass); err != nil {
+		return nil, err
+	}
+
+	return &Connector{
+		Connector: ocsql.WrapConnector(
+			connector,
+			ocsql.WithOptions(ocsql.TraceOptions{
+				AllowRoot:    false,
+				Ping:         true,
+				RowsNext:     true,
+				RowsClose:    true,
+				RowsAffected: true,
+				LastInsertID: true,
+				Query:        true,
+				QueryParams:  false,
+			}),
+		),
+		mysql: connector,
+	}, nil
+}
+
+// SetPassword changes the password used for opening new connections.
+func (c *Connector) SetPassword(pass string) error {
+	return c.mysql.setPassword(pass)
+}
+
+// mysqlConnector opens connections using the current password.
+type mysqlConnector struct {
+	config    *mysql.Config
+	connector atomic.Value // driver.Connector
+}
+
+func (c *mysqlConnector) setPassword(pass string) error {
+	config := c.config.Clone()
+	config.Passwd = pass
+
+	connector, err := mysql.NewConnector(config)
 	if err != nil {
-		return nil, errors.WithStack(err)
+		return errors.WithStack(err)
 	}
 
-	return ocsql.WrapConnector(
-		connector,
-		ocsql.WithOptions(ocsql.TraceOptions{
-			AllowRoot:    false,
-			Ping:         true,
-			RowsNext:     true,
-			RowsClose:    true,
-			RowsAffected: true,
-			LastInsertID: true,
-			Query:        true,
-			QueryParams:  false,
-		}),
-	), nil
+	c.connector.Store(connector)
+
+	return nil
+}
+
+func (c *mysqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	return c.connector.Load().(driver.Connector).Connect(ctx)
+}
+
+func (c *mysqlConnector) Driver() driver.Driver {
+	return c.connector.Load().(driver.Connector).Driver()
 }
@@ -0,0 +1,91 @@
+package errorhandler
+
+import (
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Config holds details necessary for reporting errors.
+type Config struct {
+	// Handlers lists the destinations errors are reported to.
+	// Accepted values are: log, sentry, file
+	Handlers []string
+
+	// Sentry configures reporting errors to a Sentry compatible HTTP endpoint.
+	Sentry SentryConfig
+
+	// File configures writing errors as JSON records to a file.
+	File FileConfig
+
+	// RateLimit limits the number of errors reported to external destinations (anything but log).
+	RateLimit RateLimitConfig
+
+	// Deduplication drops repeated errors (identified by their stack trace) reported to external destinations.
+	Deduplication DeduplicationConfig
+}
+
+// SentryConfig configures reporting errors to a Sentry compatible HTTP endpoint.
+type SentryConfig struct {
+	// DSN is the Sentry project DSN (eg. https://public@sentry.example.com/1).
+	DSN string
+
+	// Environment the application runs in (eg. production).
+	Environment string
+
+	// Timeout for sending an error to Sentry.
+	Timeout time.Duration
+}
+
+// FileConfig configures writing errors as JSON records to a file.
+type FileConfig struct {
+	// Path of the error record file.
+	Path string
+}
+
+// RateLimitConfig limits the number of reported errors.
+type RateLimitConfig struct {
+	// Limit is the maximum number of errors reported per second. Zero means no limit.
+	Limit float64
+
+	// Burst is the maximum number of errors reported at once.
+	Burst int
+}
+
+// DeduplicationConfig configures dropping repeated errors.
+type DeduplicationConfig struct {
+	// Window is the period during which repeated errors are dropped. Zero disables deduplication.
+	Window time.Duration
+}
+
+// Validate validates the configuration.
+func (c Config) Validate() error {
+	for _, handler := range c.Handlers {
+		switch handler {
+		case "log":
+
+		case "sentry":
+			if c.Sentry.DSN == "" {
+				return errors.New("sentry dsn is required")
+			}
+
+			if _, err := parseSentryDSN(c.Sentry.DSN); err != nil {
+				return err
+			}
+
+		case "file":
+			if c.File.Path == "" {
+				return errors.New("error file path is required")
+			}
+
+		default:
+			return errors.Errorf("unknown error handler %q (accepted values are: log, sentry, file)", handler)
+		}
+	}
+
+	if c.RateLimit.Limit < 0 {
+		return errors.New("error rate limit must not be negative")
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package errorhandler
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"emperror.dev/errors"
+)
+
+// fileHandler writes errors as JSON records (one per line) to a file.
+type fileHandler struct {
+	file      *os.File
+	extractor ContextExtractor
+
+	mu sync.Mutex
+}
+
+// newFileHandler returns a new error handler writing error records to a file.
+func newFileHandler(config FileConfig, extractor ContextExtractor) (*fileHandler, error) {
+	file, err := os.OpenFile(config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to open error file")
+	}
+
+	return &fileHandler{
+		file:      file,
+		extractor: extractor,
+	}, nil
+}
+
+func (h *fileHandler) Handle(err error) {
+	h.HandleContext(context.Background(), err)
+}
+
+func (h *fileHandler) HandleContext(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	body, merr := json.Marshal(newRecord(err, h.extractor.extract(ctx)))
+	if merr != nil {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	_, _ = h.file.Write(append(body, '\n'))
+}
+
+// Close closes the underlying file.
+func (h *fileHandler) Close() error {
+	return h.file.Close()
+}
@@ -0,0 +1,135 @@
+// Package errorhandler composes error handlers reporting errors to various destinations.
+package errorhandler
+
+import (
+	"context"
+	"io"
+
+	"emperror.dev/emperror"
+	logurhandler "emperror.dev/handler/logur"
+	"logur.dev/logur"
+)
+
+// Handler is an error handler that should be closed when the application exits
+// (to flush errors waiting to be reported).
+type Handler interface {
+	emperror.ErrorHandlerFacade
+	io.Closer
+
+	// SetRateLimit changes the rate limit of errors reported to external destinations.
+	SetRateLimit(config RateLimitConfig)
+
+	// SetSentryDSN changes the DSN errors are sent to (if Sentry is enabled).
+	SetSentryDSN(dsn string) error
+}
+
+// ContextExtractor extracts fields from a context.
+type ContextExtractor func(ctx context.Context) map[string]interface{}
+
+func (e ContextExtractor) extract(ctx context.Context) map[string]interface{} {
+	if e == nil {
+		return nil
+	}
+
+	return e(ctx)
+}
+
+// New returns a new error handler reporting errors to the configured destinations.
+// Errors handled with a context are annotated with the fields extracted from it (if an extractor is provided).
+//
+// Errors reported to external destinations (anything but log) are subject to deduplication and rate limiting.
+func New(config Config, logger logur.Logger, extractor ContextExtractor) (Handler, error) {
+	handlerNames := config.Handlers
+	if len(handlerNames) == 0 {
+		handlerNames = []string{"log"}
+	}
+
+	var handlers, reporters emperror.ErrorHandlers
+
+	h := &handler{}
+
+	for _, name := range handlerNames {
+		switch name {
+		case "log":
+			var errorLogger logurhandler.ErrorLogger = logger
+			if extractor != nil {
+				errorLogger = logur.WithContextExtractor(logger, logur.ContextExtractor(extractor))
+			}
+
+			handlers = append(handlers, logurhandler.New(errorLogger))
+
+		case "sentry":
+			handler, err := newSentryHandler(
+				config.Sentry,
+				logur.WithField(logger, "component", "sentry"),
+				extractor,
+			)
+			if err != nil {
+				_ = append(handlers, reporters...).Close()
+
+				return nil, err
+			}
+
+			h.sentry = handler
+
+			reporters = append(reporters, handler)
+
+		case "file":
+			handler, err := newFileHandler(config.File, extractor)
+			if err != nil {
+				_ = append(handlers, reporters...).Close()
+
+				return nil, err
+			}
+
+			reporters = append(reporters, handler)
+		}
+	}
+
+	h.ErrorHandlers = handlers
+
+	if len(reporters) > 0 {
+		var reporter emperror.ErrorHandlerFacade = reporters
+
+		if config.Deduplication.Window > 0 {
+			reporter = WithDeduplication(reporter, config.Deduplication)
+		}
+
+		// The rate limiter is always in place, so that the limit can be changed at runtime
+		h.rateLimit = WithRateLimit(reporter, config.RateLimit)
+
+		h.ErrorHandlers = append(h.ErrorHandlers, closer{ErrorHandlerFacade: h.rateLimit, Closer: reporters})
+	}
+
+	return h, nil
+}
+
+// handler composes the configured error handlers.
+type handler struct {
+	emperror.ErrorHandlers
+
+	rateLimit *RateLimitHandler
+	sentry    *sentryHandler
+}
+
+func (h *handler) SetRateLimit(config RateLimitConfig) {
+	if h.rateLimit == nil {
+		return
+	}
+
+	h.rateLimit.SetRateLimit(config)
+}
+
+func (h *handler) SetSentryDSN(dsn string) error {
+	if h.sentry == nil {
+		return nil
+	}
+
+	return h.sentry.SetDSN(dsn)
+}
+
+// closer keeps a reference to the closable handlers when they are wrapped by other handlers.
+type closer struct {
+	emperror.ErrorHandlerFacade
+	io.Closer
+}
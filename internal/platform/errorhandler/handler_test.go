@@ -0,0 +1,210 @@
+package errorhandler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"logur.dev/logur"
+)
+
+// fakeSentry is a local HTTP endpoint collecting Sentry envelopes.
+type fakeSentry struct {
+	events []sentryEvent
+	auth   []string
+
+	mu sync.Mutex
+}
+
+func (s *fakeSentry) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/api/42/envelope/" {
+		http.NotFound(w, r)
+
+		return
+	}
+
+	body, _ := ioutil.ReadAll(r.Body)
+	lines := bytes.SplitN(body, []byte("\n"), 3)
+
+	var event sentryEvent
+
+	if len(lines) < 3 || json.Unmarshal(lines[2], &event) != nil {
+		http.Error(w, "invalid envelope", http.StatusBadRequest)
+
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, event)
+	s.auth = append(s.auth, r.Header.Get("X-Sentry-Auth"))
+}
+
+func TestNew(t *testing.T) {
+	sentry := &fakeSentry{}
+	server := httptest.NewServer(sentry)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "errors.json")
+
+	config := Config{
+		Handlers: []string{"log", "sentry", "file"},
+		Sentry: SentryConfig{
+			DSN:         strings.Replace(server.URL, "://", "://public@", 1) + "/42",
+			Environment: "test",
+		},
+		File: FileConfig{
+			Path: path,
+		},
+		Deduplication: DeduplicationConfig{
+			Window: time.Minute,
+		},
+	}
+	require.NoError(t, config.Validate())
+
+	logger := &logur.TestLoggerFacade{}
+
+	handler, err := New(config, logger, func(_ context.Context) map[string]interface{} {
+		return map[string]interface{}{"correlation_id": "1234"}
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		handler.HandleContext(context.Background(), errors.NewWithDetails("something went wrong", "key", "value"))
+	}
+
+	handler.HandleContext(context.Background(), errors.New("something else went wrong"))
+
+	require.NoError(t, handler.Close())
+
+	// Every error is logged
+	require.Equal(t, 4, logger.Count())
+	assert.Equal(t, "1234", logger.LastEvent().Fields["correlation_id"])
+
+	// Repeated errors are only reported once
+	require.Len(t, sentry.events, 2)
+
+	event := sentry.events[0]
+
+	assert.Equal(t, "something went wrong", event.Exception.Values[0].Value)
+	assert.Equal(t, "test", event.Environment)
+	assert.Equal(t, "1234", event.Tags["correlation_id"])
+	assert.Equal(t, "value", event.Extra["key"])
+	assert.NotEmpty(t, event.Exception.Values[0].Stacktrace.Frames)
+	assert.Contains(t, sentry.auth[0], "sentry_key=public")
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var records []record
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var r record
+
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &r))
+
+		records = append(records, r)
+	}
+
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "something went wrong", records[0].Message)
+	assert.Equal(t, "1234", records[0].Details["correlation_id"])
+	assert.Equal(t, event.Fingerprint[0], records[0].Fingerprint)
+	assert.NotEqual(t, records[0].Fingerprint, records[1].Fingerprint)
+}
+
+func TestHandler_SetSentryDSN(t *testing.T) {
+	sentry := &fakeSentry{}
+	server := httptest.NewServer(sentry)
+	defer server.Close()
+
+	config := Config{
+		Handlers: []string{"sentry"},
+		Sentry: SentryConfig{
+			DSN: strings.Replace(server.URL, "://", "://public@", 1) + "/42",
+		},
+	}
+
+	handler, err := New(config, logur.NoopLogger{}, nil)
+	require.NoError(t, err)
+
+	require.Error(t, handler.SetSentryDSN("https://sentry.example.com/42"))
+	require.NoError(t, handler.SetSentryDSN(strings.Replace(server.URL, "://", "://rotated@", 1)+"/42"))
+
+	handler.Handle(errors.New("something went wrong"))
+
+	require.NoError(t, handler.Close())
+
+	require.Len(t, sentry.auth, 1)
+	assert.Contains(t, sentry.auth[0], "sentry_key=rotated")
+}
+
+func TestWithRateLimit(t *testing.T) {
+	handler := &emperror.TestErrorHandlerFacade{}
+
+	limitedHandler := WithRateLimit(handler, RateLimitConfig{Limit: 0.001, Burst: 2})
+
+	for i := 0; i < 5; i++ {
+		limitedHandler.Handle(errors.New("error"))
+	}
+
+	assert.Len(t, handler.Errors(), 2)
+
+	limitedHandler.SetRateLimit(RateLimitConfig{})
+
+	for i := 0; i < 5; i++ {
+		limitedHandler.Handle(errors.New("error"))
+	}
+
+	assert.Len(t, handler.Errors(), 7)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]Config{
+		"sentry dsn is required": {
+			Handlers: []string{"sentry"},
+		},
+		"invalid sentry dsn: public key is missing": {
+			Handlers: []string{"sentry"},
+			Sentry:   SentryConfig{DSN: "https://sentry.example.com/1"},
+		},
+		"invalid sentry dsn: project id is missing": {
+			Handlers: []string{"sentry"},
+			Sentry:   SentryConfig{DSN: "https://public@sentry.example.com/"},
+		},
+		"error file path is required": {
+			Handlers: []string{"file"},
+		},
+		`unknown error handler "email" (accepted values are: log, sentry, file)`: {
+			Handlers: []string{"email"},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.Validate()
+
+			assert.EqualError(t, err, name)
+		})
+	}
+}
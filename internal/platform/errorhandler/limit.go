@@ -0,0 +1,126 @@
+package errorhandler
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"emperror.dev/emperror"
+	"golang.org/x/time/rate"
+)
+
+// WithRateLimit returns an error handler that drops errors exceeding the rate limit.
+func WithRateLimit(handler emperror.ErrorHandlerFacade, config RateLimitConfig) *RateLimitHandler {
+	limit, burst := rateLimit(config)
+
+	return &RateLimitHandler{
+		handler: handler,
+		limiter: rate.NewLimiter(limit, burst),
+	}
+}
+
+// RateLimitHandler is an error handler that drops errors exceeding a rate limit.
+type RateLimitHandler struct {
+	handler emperror.ErrorHandlerFacade
+	limiter *rate.Limiter
+}
+
+// SetRateLimit changes the rate limit. A zero limit disables rate limiting.
+func (h *RateLimitHandler) SetRateLimit(config RateLimitConfig) {
+	limit, burst := rateLimit(config)
+
+	h.limiter.SetLimit(limit)
+	h.limiter.SetBurst(burst)
+}
+
+func rateLimit(config RateLimitConfig) (rate.Limit, int) {
+	limit := rate.Limit(config.Limit)
+	if config.Limit <= 0 {
+		limit = rate.Inf
+	}
+
+	burst := config.Burst
+	if burst < 1 {
+		burst = 1
+	}
+
+	return limit, burst
+}
+
+// Handle implements the emperror.ErrorHandler interface.
+func (h *RateLimitHandler) Handle(err error) {
+	if err == nil || !h.limiter.Allow() {
+		return
+	}
+
+	h.handler.Handle(err)
+}
+
+// HandleContext implements the emperror.ErrorHandlerContext interface.
+func (h *RateLimitHandler) HandleContext(ctx context.Context, err error) {
+	if err == nil || !h.limiter.Allow() {
+		return
+	}
+
+	h.handler.HandleContext(ctx, err)
+}
+
+// WithDeduplication returns an error handler that drops errors with the same fingerprint
+// (calculated from their stack trace) within a time window.
+func WithDeduplication(handler emperror.ErrorHandlerFacade, config DeduplicationConfig) emperror.ErrorHandlerFacade {
+	return &deduplicationHandler{
+		handler: handler,
+		window:  config.Window,
+		seen:    make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+type deduplicationHandler struct {
+	handler emperror.ErrorHandlerFacade
+	window  time.Duration
+
+	seen map[string]time.Time
+	now  func() time.Time
+
+	mu sync.Mutex
+}
+
+func (h *deduplicationHandler) Handle(err error) {
+	if err == nil || h.duplicate(err) {
+		return
+	}
+
+	h.handler.Handle(err)
+}
+
+func (h *deduplicationHandler) HandleContext(ctx context.Context, err error) {
+	if err == nil || h.duplicate(err) {
+		return
+	}
+
+	h.handler.HandleContext(ctx, err)
+}
+
+func (h *deduplicationHandler) duplicate(err error) bool {
+	fp := fingerprint(record{Message: err.Error(), StackTrace: stackTrace(err)})
+	now := h.now()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Forget expired fingerprints
+	for key, last := range h.seen {
+		if now.Sub(last) >= h.window {
+			delete(h.seen, key)
+		}
+	}
+
+	if _, ok := h.seen[fp]; ok {
+		return true
+	}
+
+	h.seen[fp] = now
+
+	return false
+}
@@ -0,0 +1,98 @@
+package errorhandler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"emperror.dev/errors"
+	"emperror.dev/errors/utils/keyval"
+)
+
+// record is the representation of an error reported to external destinations.
+type record struct {
+	Time        time.Time              `json:"time"`
+	Message     string                 `json:"message"`
+	Fingerprint string                 `json:"fingerprint"`
+	Details     map[string]interface{} `json:"details,omitempty"`
+	StackTrace  []frame                `json:"stacktrace,omitempty"`
+}
+
+// frame is a single stack frame of an error.
+type frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// newRecord returns a new record of an error.
+// Details of the error take precedence over fields extracted from the context.
+func newRecord(err error, fields map[string]interface{}) record {
+	r := record{
+		Time:       time.Now().UTC(),
+		Message:    err.Error(),
+		StackTrace: stackTrace(err),
+	}
+
+	if details := errors.GetDetails(err); len(fields) > 0 || len(details) > 0 {
+		r.Details = make(map[string]interface{}, len(fields)+len(details)/2)
+
+		for key, value := range fields {
+			r.Details[key] = value
+		}
+
+		for key, value := range keyval.ToMap(details) {
+			r.Details[key] = value
+		}
+	}
+
+	r.Fingerprint = fingerprint(r)
+
+	return r
+}
+
+func stackTrace(err error) []frame {
+	var stackTracer interface{ StackTrace() errors.StackTrace }
+	if !errors.As(err, &stackTracer) {
+		return nil
+	}
+
+	stackTrace := stackTracer.StackTrace()
+	frames := make([]frame, 0, len(stackTrace))
+
+	for _, f := range stackTrace {
+		line, _ := strconv.Atoi(fmt.Sprintf("%d", f))
+
+		// %+s formats a frame as "function\n\tpath"
+		file := fmt.Sprintf("%+s", f)
+		if i := strings.LastIndex(file, "\n\t"); i >= 0 {
+			file = file[i+2:]
+		}
+
+		frames = append(frames, frame{
+			Function: fmt.Sprintf("%n", f),
+			File:     file,
+			Line:     line,
+		})
+	}
+
+	return frames
+}
+
+// fingerprint identifies an error by its stack trace (or by its message if there is no stack trace).
+func fingerprint(r record) string {
+	h := sha256.New()
+
+	if len(r.StackTrace) == 0 {
+		_, _ = h.Write([]byte(r.Message))
+	}
+
+	for _, f := range r.StackTrace {
+		_, _ = fmt.Fprintf(h, "%s:%s:%d\n", f.Function, f.File, f.Line)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
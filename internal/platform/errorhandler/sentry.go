@@ -0,0 +1,310 @@
+package errorhandler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"logur.dev/logur"
+)
+
+// sentryQueueSize is the number of errors waiting to be sent before new ones get dropped.
+const sentryQueueSize = 30
+
+// sentryDSN holds the details of a parsed Sentry DSN.
+type sentryDSN struct {
+	endpoint  string
+	publicKey string
+}
+
+func parseSentryDSN(dsn string) (sentryDSN, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return sentryDSN{}, errors.WrapIf(err, "invalid sentry dsn")
+	}
+
+	if u.User == nil || u.User.Username() == "" {
+		return sentryDSN{}, errors.New("invalid sentry dsn: public key is missing")
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+
+	i := strings.LastIndex(path, "/")
+	if i < 0 || path[i+1:] == "" {
+		return sentryDSN{}, errors.New("invalid sentry dsn: project id is missing")
+	}
+
+	return sentryDSN{
+		endpoint:  u.Scheme + "://" + u.Host + path[:i] + "/api/" + path[i+1:] + "/envelope/",
+		publicKey: u.User.Username(),
+	}, nil
+}
+
+// sentryHandler sends errors to a Sentry compatible HTTP endpoint as envelopes.
+//
+// Errors are sent in the background, so that error handling does not block the caller.
+type sentryHandler struct {
+	dsn         sentryDSN
+	dsnMu       sync.RWMutex
+	environment string
+	serverName  string
+
+	client    *http.Client
+	logger    logur.Logger
+	extractor ContextExtractor
+
+	queue  chan record
+	done   chan struct{}
+	closed bool
+
+	mu sync.RWMutex
+}
+
+// newSentryHandler returns a new error handler sending errors to a Sentry compatible HTTP endpoint.
+func newSentryHandler(config SentryConfig, logger logur.Logger, extractor ContextExtractor) (*sentryHandler, error) {
+	dsn, err := parseSentryDSN(config.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+
+	serverName, _ := os.Hostname()
+
+	h := &sentryHandler{
+		dsn:         dsn,
+		environment: config.Environment,
+		serverName:  serverName,
+		client:      &http.Client{Timeout: timeout},
+		logger:      logger,
+		extractor:   extractor,
+		queue:       make(chan record, sentryQueueSize),
+		done:        make(chan struct{}),
+	}
+
+	go h.run()
+
+	return h, nil
+}
+
+func (h *sentryHandler) Handle(err error) {
+	h.HandleContext(context.Background(), err)
+}
+
+func (h *sentryHandler) HandleContext(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return
+	}
+
+	select {
+	case h.queue <- newRecord(err, h.extractor.extract(ctx)):
+
+	default:
+		h.logger.Warn("sentry queue is full, dropping error", map[string]interface{}{"error": err.Error()})
+	}
+}
+
+// Close sends the remaining errors in the queue and stops the handler.
+func (h *sentryHandler) Close() error {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.queue)
+	}
+	h.mu.Unlock()
+
+	<-h.done
+
+	return nil
+}
+
+// SetDSN changes the DSN errors are sent to.
+func (h *sentryHandler) SetDSN(dsn string) error {
+	parsedDSN, err := parseSentryDSN(dsn)
+	if err != nil {
+		return err
+	}
+
+	h.dsnMu.Lock()
+	h.dsn = parsedDSN
+	h.dsnMu.Unlock()
+
+	return nil
+}
+
+func (h *sentryHandler) run() {
+	defer close(h.done)
+
+	for r := range h.queue {
+		if err := h.send(r); err != nil {
+			h.logger.Warn("failed to send error to sentry", map[string]interface{}{"error": err.Error()})
+		}
+	}
+}
+
+func (h *sentryHandler) send(r record) error {
+	body, err := h.envelope(r)
+	if err != nil {
+		return err
+	}
+
+	h.dsnMu.RLock()
+	dsn := h.dsn
+	h.dsnMu.RUnlock()
+
+	req, err := http.NewRequest(http.MethodPost, dsn.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set(
+		"X-Sentry-Auth",
+		"Sentry sentry_version=7, sentry_client=mga/1.0, sentry_key="+dsn.publicKey,
+	)
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return errors.NewWithDetails("unexpected sentry response", "status", resp.StatusCode)
+	}
+
+	return nil
+}
+
+type sentryEvent struct {
+	EventID     string                 `json:"event_id"`
+	Timestamp   time.Time              `json:"timestamp"`
+	Platform    string                 `json:"platform"`
+	Level       string                 `json:"level"`
+	ServerName  string                 `json:"server_name,omitempty"`
+	Environment string                 `json:"environment,omitempty"`
+	Fingerprint []string               `json:"fingerprint"`
+	Exception   sentryExceptions       `json:"exception"`
+	Tags        map[string]string      `json:"tags,omitempty"`
+	Extra       map[string]interface{} `json:"extra,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryException struct {
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryFrame struct {
+	Function string `json:"function"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// sentryTags lists error details promoted to Sentry tags (to make them searchable).
+// nolint: gochecknoglobals
+var sentryTags = []string{"correlation_id", "operation_name", "trace_id", "component"}
+
+func (h *sentryHandler) envelope(r record) ([]byte, error) {
+	eventID, err := newEventID()
+	if err != nil {
+		return nil, err
+	}
+
+	event := sentryEvent{
+		EventID:     eventID,
+		Timestamp:   r.Time,
+		Platform:    "go",
+		Level:       "error",
+		ServerName:  h.serverName,
+		Environment: h.environment,
+		Fingerprint: []string{r.Fingerprint},
+		Exception: sentryExceptions{
+			Values: []sentryException{{Value: r.Message}},
+		},
+		Extra: r.Details,
+	}
+
+	for _, tag := range sentryTags {
+		if value, ok := r.Details[tag]; ok {
+			if event.Tags == nil {
+				event.Tags = make(map[string]string)
+			}
+
+			event.Tags[tag] = fmt.Sprint(value)
+		}
+	}
+
+	if len(r.StackTrace) > 0 {
+		frames := make([]sentryFrame, 0, len(r.StackTrace))
+
+		// Sentry expects the oldest frame first
+		for i := len(r.StackTrace) - 1; i >= 0; i-- {
+			f := r.StackTrace[i]
+
+			frames = append(frames, sentryFrame{
+				Function: f.Function,
+				AbsPath:  f.File,
+				Lineno:   f.Line,
+				InApp:    !strings.Contains(f.File, "/pkg/mod/") && !strings.HasPrefix(f.File, "runtime/"),
+			})
+		}
+
+		event.Exception.Values[0].Stacktrace = &sentryStacktrace{Frames: frames}
+	}
+
+	eventBody, err := json.Marshal(event)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+
+	_ = encoder.Encode(map[string]interface{}{"event_id": eventID, "sent_at": time.Now().UTC()})
+	_ = encoder.Encode(map[string]interface{}{"type": "event", "length": len(eventBody)})
+
+	buf.Write(eventBody)
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+func newEventID() (string, error) {
+	id := make([]byte, 16)
+
+	if _, err := rand.Read(id); err != nil {
+		return "", errors.WithStack(err)
+	}
+
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,47 @@
+package gosundheit
+
+import (
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Config holds details necessary for running health checks.
+type Config struct {
+	// Period is the time between successive check executions.
+	Period time.Duration
+
+	// Disk configures the disk space check.
+	Disk DiskConfig
+}
+
+// DiskConfig configures the disk space check.
+type DiskConfig struct {
+	// Enabled registers the disk space check.
+	Enabled bool
+
+	// Path is a path on the file system to check.
+	Path string
+
+	// MinFreePercent is the minimum free disk space (in percent) required for the check to pass.
+	MinFreePercent float64
+}
+
+// Validate validates the configuration.
+func (c Config) Validate() error {
+	if c.Period <= 0 {
+		return errors.New("health check period must be positive")
+	}
+
+	if c.Disk.Enabled {
+		if c.Disk.Path == "" {
+			return errors.New("disk check path is required")
+		}
+
+		if c.Disk.MinFreePercent < 0 || c.Disk.MinFreePercent > 100 {
+			return errors.New("disk check minimum free space must be between 0 and 100 percent")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+//go:build linux || darwin
+// +build linux darwin
+
+package gosundheit
+
+import (
+	"syscall"
+
+	"emperror.dev/errors"
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// NewDiskSpaceCheck returns a check that fails when free disk space drops below a threshold.
+func NewDiskSpaceCheck(config DiskConfig) checks.Check {
+	return &checks.CustomCheck{
+		CheckName: "disk.space",
+		CheckFunc: func() (interface{}, error) {
+			var stat syscall.Statfs_t
+
+			if err := syscall.Statfs(config.Path, &stat); err != nil {
+				return nil, errors.WrapIf(err, "failed to get file system statistics")
+			}
+
+			total := float64(stat.Blocks) * float64(stat.Bsize)
+			free := float64(stat.Bavail) * float64(stat.Bsize)
+
+			var freePercent float64
+			if total > 0 {
+				freePercent = free / total * 100
+			}
+
+			details := map[string]interface{}{
+				"path":         config.Path,
+				"free_bytes":   uint64(free),
+				"free_percent": freePercent,
+			}
+
+			if freePercent < config.MinFreePercent {
+				return details, errors.Errorf("free disk space is below %.2f%%", config.MinFreePercent)
+			}
+
+			return details, nil
+		},
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package gosundheit
+
+import (
+	"emperror.dev/errors"
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// NewDiskSpaceCheck returns a check that fails when free disk space drops below a threshold.
+//
+// Disk space checks are not supported on this platform, so the check always fails.
+func NewDiskSpaceCheck(_ DiskConfig) checks.Check {
+	return &checks.CustomCheck{
+		CheckName: "disk.space",
+		CheckFunc: func() (interface{}, error) {
+			return nil, errors.New("disk space check is not supported on this platform")
+		},
+	}
+}
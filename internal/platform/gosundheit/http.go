@@ -0,0 +1,87 @@
+package gosundheit
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	health "github.com/AppsFlyer/go-sundheit"
+)
+
+// healthStatus is the JSON representation of health check results.
+type healthStatus struct {
+	Status string                 `json:"status"`
+	Checks map[string]checkStatus `json:"checks"`
+}
+
+type checkStatus struct {
+	Status             string      `json:"status"`
+	Probes             string      `json:"probes"`
+	Details            interface{} `json:"details,omitempty"`
+	Error              string      `json:"error,omitempty"`
+	Timestamp          time.Time   `json:"timestamp"`
+	Duration           string      `json:"duration"`
+	ContiguousFailures int64       `json:"contiguousFailures"`
+	TimeOfFirstFailure *time.Time  `json:"timeOfFirstFailure,omitempty"`
+}
+
+// HTTPHandler returns an HTTP handler reporting the results of every check.
+func (r *Registry) HTTPHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		results, healthy := r.AllResults()
+
+		r.writeResults(w, results, healthy)
+	})
+}
+
+// ProbeHTTPHandler returns an HTTP handler reporting the results of checks affecting a probe.
+func (r *Registry) ProbeHTTPHandler(probe Probe) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		results, healthy := r.Results(probe)
+
+		r.writeResults(w, results, healthy)
+	})
+}
+
+func (r *Registry) writeResults(w http.ResponseWriter, results map[string]health.Result, healthy bool) {
+	status := healthStatus{
+		Status: statusString(healthy),
+		Checks: make(map[string]checkStatus, len(results)),
+	}
+
+	for name, result := range results {
+		check := checkStatus{
+			Status:             statusString(result.IsHealthy()),
+			Probes:             r.Probes(name).String(),
+			Details:            result.Details,
+			Timestamp:          result.Timestamp,
+			Duration:           result.Duration.String(),
+			ContiguousFailures: result.ContiguousFailures,
+			TimeOfFirstFailure: result.TimeOfFirstFailure,
+		}
+
+		if result.Error != nil {
+			check.Error = result.Error.Error()
+		}
+
+		status.Checks[name] = check
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if healthy {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+func statusString(healthy bool) string {
+	if healthy {
+		return "pass"
+	}
+
+	return "fail"
+}
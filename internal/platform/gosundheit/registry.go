@@ -0,0 +1,263 @@
+package gosundheit
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	health "github.com/AppsFlyer/go-sundheit"
+)
+
+// Probe is a kind of health probe (as defined by Kubernetes) a check affects.
+type Probe uint8
+
+const (
+	// Liveness checks fail when the application is broken and should be restarted.
+	Liveness Probe = 1 << iota
+
+	// Readiness checks fail when the application cannot serve traffic.
+	Readiness
+
+	// Startup checks have to pass once before the application is considered started.
+	Startup
+)
+
+// String returns the names of the probes.
+func (p Probe) String() string {
+	var names []string
+
+	for _, probe := range []Probe{Liveness, Readiness, Startup} {
+		if p&probe != 0 {
+			names = append(names, probe.name())
+		}
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, ",")
+}
+
+func (p Probe) name() string {
+	switch p {
+	case Liveness:
+		return "liveness"
+
+	case Readiness:
+		return "readiness"
+
+	case Startup:
+		return "startup"
+	}
+
+	return "unknown"
+}
+
+// Registry keeps track of health checks and the probes they affect.
+type Registry struct {
+	health  health.Health
+	probes  map[string]Probe
+	configs map[string]health.Config
+
+	// started is set once every startup check passed.
+	started bool
+
+	// shuttingDown fails the readiness probe regardless of check results.
+	shuttingDown bool
+	shutdownAt   time.Time
+
+	// period is the latest execution period set by SetExecutionPeriod.
+	period   time.Duration
+	periodMu sync.Mutex
+
+	mu sync.RWMutex
+}
+
+// NewRegistry returns a new Registry.
+func NewRegistry(h health.Health) *Registry {
+	return &Registry{
+		health:  h,
+		probes:  make(map[string]Probe),
+		configs: make(map[string]health.Config),
+	}
+}
+
+// RegisterCheck registers a health check affecting the given probes.
+// Checks affecting no probes (zero value) only appear in the overall health status.
+func (r *Registry) RegisterCheck(config *health.Config, probes Probe) error {
+	err := r.health.RegisterCheck(config)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.probes[config.Check.Name()] = probes
+	r.configs[config.Check.Name()] = *config
+
+	return nil
+}
+
+// SetExecutionPeriod changes the execution period of every registered check.
+//
+// Checks are registered again with the new period in the background,
+// since deregistering a check waits for its running execution (which may take a while).
+// They keep their last status until they are executed again.
+// The returned channel receives the result once the checks are registered again.
+//
+// Concurrent changes are applied one after the other, the last one wins.
+func (r *Registry) SetExecutionPeriod(period time.Duration) <-chan error {
+	r.mu.Lock()
+	r.period = period
+	r.mu.Unlock()
+
+	errc := make(chan error, 1)
+
+	go func() {
+		r.periodMu.Lock()
+		defer r.periodMu.Unlock()
+
+		errc <- r.applyExecutionPeriod()
+	}()
+
+	return errc
+}
+
+func (r *Registry) applyExecutionPeriod() error {
+	r.mu.RLock()
+	period := r.period
+	configs := make([]health.Config, 0, len(r.configs))
+	for _, config := range r.configs {
+		configs = append(configs, config)
+	}
+	r.mu.RUnlock()
+
+	results, _ := r.health.Results()
+
+	for _, config := range configs {
+		if config.ExecutionPeriod == period {
+			continue
+		}
+
+		name := config.Check.Name()
+
+		r.health.Deregister(name)
+
+		// Deregistered checks are cleaned up asynchronously
+		if err := r.waitForDeregister(name); err != nil {
+			return err
+		}
+
+		config.ExecutionPeriod = period
+		config.InitialDelay = 0
+		config.InitiallyPassing = results[name].IsHealthy()
+
+		if err := r.health.RegisterCheck(&config); err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		r.configs[name] = config
+		r.mu.Unlock()
+	}
+
+	return nil
+}
+
+// deregisterTimeout is the maximum time to wait for a check to be deregistered.
+const deregisterTimeout = 10 * time.Second
+
+func (r *Registry) waitForDeregister(name string) error {
+	deadline := time.Now().Add(deregisterTimeout)
+
+	for time.Now().Before(deadline) {
+		results, _ := r.health.Results()
+		if _, ok := results[name]; !ok {
+			return nil
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return errors.NewWithDetails("timed out waiting for health check to be deregistered", "check", name)
+}
+
+// shutdownCheckName is the name of the pseudo check failing the readiness probe during shutdown.
+const shutdownCheckName = "shutdown"
+
+// SetShuttingDown makes the readiness probe fail, so that no new traffic is routed to the application.
+func (r *Registry) SetShuttingDown() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.shuttingDown {
+		return
+	}
+
+	r.shuttingDown = true
+	r.shutdownAt = time.Now()
+	r.probes[shutdownCheckName] = Readiness
+}
+
+// Results returns the results of checks affecting a probe and whether the probe passes.
+//
+// The startup probe passes (and keeps passing) once every startup check passed.
+func (r *Registry) Results(probe Probe) (map[string]health.Result, bool) {
+	results, _ := r.health.Results()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	healthy := true
+	probeResults := make(map[string]health.Result)
+
+	for name, result := range results {
+		if r.probes[name]&probe == 0 {
+			continue
+		}
+
+		probeResults[name] = result
+
+		if !result.IsHealthy() {
+			healthy = false
+		}
+	}
+
+	if probe&Readiness != 0 && r.shuttingDown {
+		shutdownAt := r.shutdownAt
+
+		probeResults[shutdownCheckName] = health.Result{
+			Error:              errors.New("application is shutting down"),
+			Timestamp:          time.Now(),
+			TimeOfFirstFailure: &shutdownAt,
+		}
+
+		healthy = false
+	}
+
+	if probe == Startup {
+		if healthy {
+			r.started = true
+		}
+
+		return probeResults, r.started
+	}
+
+	return probeResults, healthy
+}
+
+// AllResults returns the results of every check and whether all of them pass.
+func (r *Registry) AllResults() (map[string]health.Result, bool) {
+	return r.health.Results()
+}
+
+// Probes returns the probes affected by a check.
+func (r *Registry) Probes(name string) Probe {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.probes[name]
+}
@@ -0,0 +1,252 @@
+package gosundheit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"emperror.dev/errors"
+	health "github.com/AppsFlyer/go-sundheit"
+	"github.com/AppsFlyer/go-sundheit/checks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCheck(name string, healthy *int32) checks.Check {
+	return &checks.CustomCheck{
+		CheckName: name,
+		CheckFunc: func() (interface{}, error) {
+			if atomic.LoadInt32(healthy) == 0 {
+				return "details", errors.New("check failed")
+			}
+
+			return "details", nil
+		},
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	h := health.New()
+	defer h.DeregisterAll()
+
+	registry := NewRegistry(h)
+
+	var dbHealthy, routerHealthy, exporterHealthy int32 = 1, 1, 0
+
+	checks := map[string]struct {
+		healthy *int32
+		probes  Probe
+	}{
+		"db":       {&dbHealthy, Readiness | Startup},
+		"router":   {&routerHealthy, Liveness | Startup},
+		"exporter": {&exporterHealthy, 0},
+	}
+
+	for name, check := range checks {
+		err := registry.RegisterCheck(&health.Config{
+			Check:           newCheck(name, check.healthy),
+			ExecutionPeriod: 10 * time.Millisecond,
+		}, check.probes)
+		require.NoError(t, err)
+	}
+
+	probeHealthy := func(probe Probe) func() bool {
+		return func() bool {
+			_, healthy := registry.Results(probe)
+
+			return healthy
+		}
+	}
+
+	assert.Eventually(t, probeHealthy(Liveness), time.Second, 10*time.Millisecond)
+	assert.Eventually(t, probeHealthy(Readiness), time.Second, 10*time.Millisecond)
+	assert.Eventually(t, probeHealthy(Startup), time.Second, 10*time.Millisecond)
+
+	// A failing check not affecting any probes only shows up in the overall status
+	_, healthy := registry.AllResults()
+	assert.False(t, healthy)
+
+	results, _ := registry.Results(Readiness)
+	assert.Len(t, results, 1)
+	assert.Contains(t, results, "db")
+
+	atomic.StoreInt32(&dbHealthy, 0)
+
+	assert.Eventually(t, func() bool { return !probeHealthy(Readiness)() }, time.Second, 10*time.Millisecond)
+	assert.True(t, probeHealthy(Liveness)())
+
+	// The startup probe keeps passing once it passed
+	assert.True(t, probeHealthy(Startup)())
+
+	rec := httptest.NewRecorder()
+	registry.ProbeHTTPHandler(Readiness).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/ready", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var status healthStatus
+
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&status))
+
+	assert.Equal(t, "fail", status.Status)
+	assert.Equal(t, "fail", status.Checks["db"].Status)
+	assert.Equal(t, "check failed", status.Checks["db"].Error)
+	assert.Equal(t, "readiness,startup", status.Checks["db"].Probes)
+	assert.Equal(t, "details", status.Checks["db"].Details)
+}
+
+func TestRegistry_ProbeHTTPHandler_Liveness(t *testing.T) {
+	h := health.New()
+	defer h.DeregisterAll()
+
+	registry := NewRegistry(h)
+
+	var routerHealthy int32 = 1
+
+	err := registry.RegisterCheck(&health.Config{
+		Check:           newCheck("router", &routerHealthy),
+		ExecutionPeriod: 10 * time.Millisecond,
+	}, Liveness)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz/live", registry.ProbeHTTPHandler(Liveness))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	statusCode := func() int {
+		resp, err := http.Get(server.URL + "/healthz/live")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	assert.Eventually(t, func() bool { return statusCode() == http.StatusOK }, time.Second, 10*time.Millisecond)
+
+	atomic.StoreInt32(&routerHealthy, 0)
+
+	assert.Eventually(
+		t,
+		func() bool { return statusCode() == http.StatusServiceUnavailable },
+		time.Second,
+		10*time.Millisecond,
+	)
+}
+
+func TestRegistry_SetShuttingDown(t *testing.T) {
+	h := health.New()
+	defer h.DeregisterAll()
+
+	registry := NewRegistry(h)
+
+	dbHealthy := int32(1)
+
+	err := registry.RegisterCheck(&health.Config{
+		Check:           newCheck("db", &dbHealthy),
+		ExecutionPeriod: 10 * time.Millisecond,
+	}, Liveness|Readiness)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, healthy := registry.Results(Readiness)
+
+		return healthy
+	}, time.Second, 10*time.Millisecond)
+
+	registry.SetShuttingDown()
+
+	results, healthy := registry.Results(Readiness)
+	assert.False(t, healthy)
+	assert.Contains(t, results, "shutdown")
+
+	_, healthy = registry.Results(Liveness)
+	assert.True(t, healthy)
+}
+
+func TestRegistry_SetExecutionPeriod(t *testing.T) {
+	h := health.New()
+	defer h.DeregisterAll()
+
+	registry := NewRegistry(h)
+
+	var executions int32
+
+	err := registry.RegisterCheck(&health.Config{
+		Check: &checks.CustomCheck{
+			CheckName: "counter",
+			CheckFunc: func() (interface{}, error) {
+				atomic.AddInt32(&executions, 1)
+
+				return nil, nil
+			},
+		},
+		ExecutionPeriod: time.Hour,
+	}, Readiness)
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&executions) == 1 }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, <-registry.SetExecutionPeriod(10*time.Millisecond))
+
+	// The check keeps passing while it is registered again
+	_, healthy := registry.Results(Readiness)
+	assert.True(t, healthy)
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&executions) > 3 }, time.Second, 10*time.Millisecond)
+}
+
+func TestRegistry_SetExecutionPeriod_RunningCheck(t *testing.T) {
+	h := health.New()
+	defer h.DeregisterAll()
+
+	registry := NewRegistry(h)
+
+	running := make(chan struct{}, 1)
+	release := make(chan struct{})
+
+	err := registry.RegisterCheck(&health.Config{
+		Check: &checks.CustomCheck{
+			CheckName: "blocking",
+			CheckFunc: func() (interface{}, error) {
+				select {
+				case running <- struct{}{}:
+				default:
+				}
+
+				<-release
+
+				return nil, nil
+			},
+		},
+		ExecutionPeriod: time.Hour,
+	}, Readiness)
+	require.NoError(t, err)
+
+	<-running
+
+	// Changing the period does not wait for the running check
+	errc := registry.SetExecutionPeriod(time.Minute)
+	latestErrc := registry.SetExecutionPeriod(time.Second)
+
+	select {
+	case err := <-errc:
+		t.Fatalf("execution period changed while the check is running: %v", err)
+
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+
+	require.NoError(t, <-errc)
+	require.NoError(t, <-latestErrc)
+
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	// The last change wins
+	assert.Equal(t, time.Second, registry.configs["blocking"].ExecutionPeriod)
+}
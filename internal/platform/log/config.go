@@ -1,9 +1,22 @@
 package log
 
+import (
+	"time"
+
+	"emperror.dev/errors"
+	"logur.dev/logur"
+)
+
 // Config holds details necessary for logging.
 type Config struct {
+	// Backend specifies the logging library used under the hood.
+	// Accepted values are: logrus (default), zap, zerolog, slog
+	Backend string
+
 	// Format specifies the output log format.
-	// Accepted values are: json, logfmt
+	// Accepted values are: json, logfmt, console
+	//
+	// Backends without logfmt support (zap, zerolog) fall back to console.
 	Format string
 
 	// Level is the minimum log level that should appear on the output.
@@ -11,4 +24,85 @@ type Config struct {
 
 	// NoColor makes sure that no log output gets colorized.
 	NoColor bool
+
+	// Outputs lists the destinations log events are written to.
+	// Defaults to standard output.
+	Outputs []OutputConfig
+}
+
+// OutputConfig describes a log destination.
+type OutputConfig struct {
+	// Type specifies the kind of the destination.
+	// Accepted values are: stdout, stderr, file, syslog
+	Type string
+
+	// Path is the log file path (file output).
+	Path string
+
+	// MaxSize is the maximum size of the log file in megabytes before it gets rotated (file output).
+	MaxSize int
+
+	// MaxAge is the maximum time to retain rotated log files (file output, rounded up to days).
+	MaxAge time.Duration
+
+	// MaxBackups is the maximum number of rotated log files to retain (file output).
+	MaxBackups int
+
+	// RotationInterval rotates the log file periodically regardless of its size (file output).
+	RotationInterval time.Duration
+
+	// Compress enables compressing rotated log files (file output).
+	Compress bool
+
+	// Network and Address of a remote syslog server (syslog output).
+	// Local syslog is used when empty.
+	Network string
+	Address string
+
+	// Tag is the syslog tag (syslog output).
+	Tag string
+}
+
+// Validate validates the configuration.
+func (c Config) Validate() error {
+	switch c.Backend {
+	case "", "logrus", "zap", "zerolog", "slog":
+	default:
+		return errors.New("log backend must be logrus, zap, zerolog or slog")
+	}
+
+	switch c.Format {
+	case "", "json", "logfmt", "console":
+	default:
+		return errors.New("log format must be json, logfmt or console")
+	}
+
+	if _, ok := logur.ParseLevel(c.Level); c.Level != "" && !ok {
+		return errors.New("log level must be trace, debug, info, warn or error")
+	}
+
+	for _, output := range c.Outputs {
+		if err := output.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the configuration.
+func (c OutputConfig) Validate() error {
+	switch c.Type {
+	case "stdout", "stderr", "syslog":
+
+	case "file":
+		if c.Path == "" {
+			return errors.New("log file path is required")
+		}
+
+	default:
+		return errors.New("log output type must be stdout, stderr, file or syslog")
+	}
+
+	return nil
 }
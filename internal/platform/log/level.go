@@ -0,0 +1,138 @@
+package log
+
+import (
+	"sync"
+
+	"logur.dev/logur"
+)
+
+// ComponentKey is the log field key used for identifying the component emitting a log event.
+const ComponentKey = "component"
+
+// LevelController holds the minimum log level of a logger and allows changing it at runtime.
+//
+// Besides the global level, a level can be set for individual components
+// (identified by the "component" field of log events).
+type LevelController struct {
+	level      logur.Level
+	components map[string]logur.Level
+
+	// toggledFrom holds the level before a debug toggle (if any).
+	toggledFrom *logur.Level
+
+	mu sync.RWMutex
+}
+
+// NewLevelController returns a new LevelController initialized from the configuration.
+//
+// It falls back to info level if the configured level is invalid.
+func NewLevelController(config Config) *LevelController {
+	level, ok := logur.ParseLevel(config.Level)
+	if !ok {
+		level = logur.Info
+	}
+
+	return &LevelController{
+		level:      level,
+		components: make(map[string]logur.Level),
+	}
+}
+
+// Level returns the global minimum log level.
+func (c *LevelController) Level() logur.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.level
+}
+
+// SetLevel sets the global minimum log level.
+func (c *LevelController) SetLevel(level logur.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.level = level
+	c.toggledFrom = nil
+}
+
+// ComponentLevels returns the minimum log level of every component with a level of its own.
+func (c *LevelController) ComponentLevels() map[string]logur.Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	levels := make(map[string]logur.Level, len(c.components))
+
+	for component, level := range c.components {
+		levels[component] = level
+	}
+
+	return levels
+}
+
+// SetComponentLevel sets the minimum log level of a component.
+func (c *LevelController) SetComponentLevel(component string, level logur.Level) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.components[component] = level
+}
+
+// ResetComponentLevel removes the minimum log level of a component,
+// so that the component falls back to the global level.
+func (c *LevelController) ResetComponentLevel(component string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.components, component)
+}
+
+// ToggleDebug switches the global level to debug or back to the level it had before the previous toggle.
+// It returns the new global level.
+func (c *LevelController) ToggleDebug() logur.Level {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.toggledFrom != nil {
+		c.level = *c.toggledFrom
+		c.toggledFrom = nil
+
+		return c.level
+	}
+
+	previous := c.level
+
+	c.level = logur.Debug
+	c.toggledFrom = &previous
+
+	return c.level
+}
+
+// LevelEnabled checks if a level is enabled for any of the components.
+func (c *LevelController) LevelEnabled(level logur.Level) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if level >= c.level {
+		return true
+	}
+
+	for _, componentLevel := range c.components {
+		if level >= componentLevel {
+			return true
+		}
+	}
+
+	return false
+}
+
+// componentLevelEnabled checks if a level is enabled for a component.
+func (c *LevelController) componentLevelEnabled(component string, level logur.Level) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if componentLevel, ok := c.components[component]; ok && component != "" {
+		return level >= componentLevel
+	}
+
+	return level >= c.level
+}
@@ -0,0 +1,101 @@
+package log
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"emperror.dev/errors"
+	"logur.dev/logur"
+)
+
+// levelStatus is the representation of the current log levels.
+type levelStatus struct {
+	Level      string            `json:"level"`
+	Components map[string]string `json:"components"`
+}
+
+// levelChange is a request to change the log level, optionally for a single component.
+//
+// An empty level resets the level of the component to the global level.
+type levelChange struct {
+	Level     string `json:"level"`
+	Component string `json:"component"`
+}
+
+// LevelHTTPHandler returns an HTTP handler for inspecting (GET) and changing (PUT) log levels at runtime.
+func LevelHTTPHandler(controller *LevelController, logger logur.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			// Nothing to do
+
+		case http.MethodPut:
+			var change levelChange
+
+			err := json.NewDecoder(r.Body).Decode(&change)
+			if err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+
+				return
+			}
+
+			err = applyLevelChange(controller, change)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+
+				return
+			}
+
+			logger.Info("log level changed", map[string]interface{}{
+				"level":            change.Level,
+				"target_component": change.Component,
+			})
+
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+			return
+		}
+
+		status := levelStatus{
+			Level:      controller.Level().String(),
+			Components: make(map[string]string),
+		}
+
+		for component, level := range controller.ComponentLevels() {
+			status.Components[component] = level.String()
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		_ = json.NewEncoder(w).Encode(status)
+	})
+}
+
+func applyLevelChange(controller *LevelController, change levelChange) error {
+	if change.Level == "" {
+		if change.Component == "" {
+			return errors.New("log level is required")
+		}
+
+		controller.ResetComponentLevel(change.Component)
+
+		return nil
+	}
+
+	level, ok := logur.ParseLevel(change.Level)
+	if !ok {
+		return errors.Errorf("invalid log level %q (accepted values are: trace, debug, info, warn, error)", change.Level)
+	}
+
+	if change.Component == "" {
+		controller.SetLevel(level)
+
+		return nil
+	}
+
+	controller.SetComponentLevel(change.Component, level)
+
+	return nil
+}
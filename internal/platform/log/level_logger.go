@@ -0,0 +1,98 @@
+package log
+
+import (
+	"context"
+
+	"logur.dev/logur"
+)
+
+// WithLevelController returns a logger that drops log events below the level set in the controller.
+//
+// The level is checked against the component of the log event (if any),
+// so the logger should be placed at the bottom of the logger chain where every field is available.
+func WithLevelController(logger logur.LoggerFacade, controller *LevelController) logur.LoggerFacade {
+	return levelLogger{
+		logger:     logger,
+		controller: controller,
+	}
+}
+
+type levelLogger struct {
+	logger     logur.LoggerFacade
+	controller *LevelController
+}
+
+func (l levelLogger) Trace(msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Trace, fields) {
+		l.logger.Trace(msg, fields...)
+	}
+}
+
+func (l levelLogger) Debug(msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Debug, fields) {
+		l.logger.Debug(msg, fields...)
+	}
+}
+
+func (l levelLogger) Info(msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Info, fields) {
+		l.logger.Info(msg, fields...)
+	}
+}
+
+func (l levelLogger) Warn(msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Warn, fields) {
+		l.logger.Warn(msg, fields...)
+	}
+}
+
+func (l levelLogger) Error(msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Error, fields) {
+		l.logger.Error(msg, fields...)
+	}
+}
+
+func (l levelLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Trace, fields) {
+		l.logger.TraceContext(ctx, msg, fields...)
+	}
+}
+
+func (l levelLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Debug, fields) {
+		l.logger.DebugContext(ctx, msg, fields...)
+	}
+}
+
+func (l levelLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Info, fields) {
+		l.logger.InfoContext(ctx, msg, fields...)
+	}
+}
+
+func (l levelLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Warn, fields) {
+		l.logger.WarnContext(ctx, msg, fields...)
+	}
+}
+
+func (l levelLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	if l.enabled(logur.Error, fields) {
+		l.logger.ErrorContext(ctx, msg, fields...)
+	}
+}
+
+// LevelEnabled implements the logur.LevelEnabler interface.
+func (l levelLogger) LevelEnabled(level logur.Level) bool {
+	return l.controller.LevelEnabled(level)
+}
+
+func (l levelLogger) enabled(level logur.Level, fields []map[string]interface{}) bool {
+	var component string
+
+	if len(fields) > 0 {
+		component, _ = fields[0][ComponentKey].(string)
+	}
+
+	return l.controller.componentLevelEnabled(component, level)
+}
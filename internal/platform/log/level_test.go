@@ -0,0 +1,107 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"logur.dev/logur"
+)
+
+func TestWithLevelController(t *testing.T) {
+	testLogger := &logur.TestLoggerFacade{}
+	controller := NewLevelController(Config{Level: "info"})
+
+	logger := WithLevelController(testLogger, controller)
+
+	logger.Debug("message")
+	assert.Equal(t, 0, testLogger.Count())
+
+	controller.SetComponentLevel("watermill", logur.Debug)
+
+	logur.WithField(logger, ComponentKey, "watermill").Debug("message")
+	assert.Equal(t, 1, testLogger.Count())
+
+	logur.WithField(logger, ComponentKey, "mysql").Debug("message")
+	assert.Equal(t, 1, testLogger.Count())
+
+	controller.ResetComponentLevel("watermill")
+
+	logur.WithField(logger, ComponentKey, "watermill").Debug("message")
+	assert.Equal(t, 1, testLogger.Count())
+}
+
+func TestLevelController_ToggleDebug(t *testing.T) {
+	controller := NewLevelController(Config{Level: "warn"})
+
+	assert.Equal(t, logur.Debug, controller.ToggleDebug())
+	assert.Equal(t, logur.Warn, controller.ToggleDebug())
+}
+
+func TestLevelHTTPHandler(t *testing.T) {
+	controller := NewLevelController(Config{Level: "info"})
+	handler := LevelHTTPHandler(controller, logur.NoopLogger{})
+
+	tests := []struct {
+		name   string
+		method string
+		body   string
+		status int
+		want   string
+	}{
+		{
+			name:   "get",
+			method: http.MethodGet,
+			status: http.StatusOK,
+			want:   `{"level":"info","components":{}}`,
+		},
+		{
+			name:   "set global level",
+			method: http.MethodPut,
+			body:   `{"level":"debug"}`,
+			status: http.StatusOK,
+			want:   `{"level":"debug","components":{}}`,
+		},
+		{
+			name:   "set component level",
+			method: http.MethodPut,
+			body:   `{"level":"trace","component":"watermill"}`,
+			status: http.StatusOK,
+			want:   `{"level":"debug","components":{"watermill":"trace"}}`,
+		},
+		{
+			name:   "reset component level",
+			method: http.MethodPut,
+			body:   `{"component":"watermill"}`,
+			status: http.StatusOK,
+			want:   `{"level":"debug","components":{}}`,
+		},
+		{
+			name:   "invalid level",
+			method: http.MethodPut,
+			body:   `{"level":"verbose"}`,
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "method not allowed",
+			method: http.MethodPost,
+			status: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(test.method, "/loglevel", strings.NewReader(test.body))
+
+		handler.ServeHTTP(rec, req)
+
+		require.Equal(t, test.status, rec.Code, test.name)
+
+		if test.want != "" {
+			assert.JSONEq(t, test.want, rec.Body.String(), test.name)
+		}
+	}
+}
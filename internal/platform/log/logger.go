@@ -2,18 +2,166 @@
 package log
 
 import (
-	"os"
+	"context"
+	"io"
+	"sync"
+	"sync/atomic"
 
+	"emperror.dev/errors"
 	"github.com/sirupsen/logrus"
 	logrusadapter "logur.dev/adapter/logrus"
 	"logur.dev/logur"
 )
 
 // NewLogger creates a new logger.
-func NewLogger(config Config) logur.LoggerFacade {
+//
+// The minimum log level is controlled by the level controller, so it can be changed at runtime.
+func NewLogger(config Config, levels *LevelController) (*Logger, error) {
+	w, err := newOutput(config.Outputs)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := &Logger{
+		config: config,
+		w:      w,
+		levels: levels,
+	}
+
+	if err := logger.setBackend(config); err != nil {
+		return nil, err
+	}
+
+	return logger, nil
+}
+
+func newBackend(config Config, w io.Writer) (logur.LoggerFacade, error) {
+	switch config.Backend {
+	case "", "logrus":
+		return newLogrusLogger(config, w), nil
+
+	case "zap":
+		return newZapLogger(config, w), nil
+
+	case "zerolog":
+		return newZerologLogger(config, w), nil
+
+	case "slog":
+		return newSlogLogger(config, w)
+	}
+
+	return nil, errors.Errorf("unknown log backend %q", config.Backend)
+}
+
+// Logger is a logger whose format can be changed at runtime.
+type Logger struct {
+	config Config
+	w      io.WriteCloser
+	levels *LevelController
+
+	logger atomic.Value
+	mu     sync.Mutex
+}
+
+// Close closes the outputs of the logger (eg. log files).
+// The logger should not be used afterwards.
+func (l *Logger) Close() error {
+	return l.w.Close()
+}
+
+// SetFormat changes the output format of the logger.
+//
+// Backend and outputs cannot be changed at runtime.
+func (l *Logger) SetFormat(format string, noColor bool) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	config := l.config
+	config.Format = format
+	config.NoColor = noColor
+
+	if err := config.Validate(); err != nil {
+		return err
+	}
+
+	return l.setBackend(config)
+}
+
+func (l *Logger) setBackend(config Config) error {
+	backend, err := newBackend(config, l.w)
+	if err != nil {
+		return err
+	}
+
+	l.config = config
+	l.logger.Store(WithLevelController(backend, l.levels))
+
+	return nil
+}
+
+func (l *Logger) current() logur.LoggerFacade {
+	return l.logger.Load().(logur.LoggerFacade)
+}
+
+// Trace implements the logur.Logger interface.
+func (l *Logger) Trace(msg string, fields ...map[string]interface{}) {
+	l.current().Trace(msg, fields...)
+}
+
+// Debug implements the logur.Logger interface.
+func (l *Logger) Debug(msg string, fields ...map[string]interface{}) {
+	l.current().Debug(msg, fields...)
+}
+
+// Info implements the logur.Logger interface.
+func (l *Logger) Info(msg string, fields ...map[string]interface{}) {
+	l.current().Info(msg, fields...)
+}
+
+// Warn implements the logur.Logger interface.
+func (l *Logger) Warn(msg string, fields ...map[string]interface{}) {
+	l.current().Warn(msg, fields...)
+}
+
+// Error implements the logur.Logger interface.
+func (l *Logger) Error(msg string, fields ...map[string]interface{}) {
+	l.current().Error(msg, fields...)
+}
+
+// TraceContext implements the logur.LoggerContext interface.
+func (l *Logger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.current().TraceContext(ctx, msg, fields...)
+}
+
+// DebugContext implements the logur.LoggerContext interface.
+func (l *Logger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.current().DebugContext(ctx, msg, fields...)
+}
+
+// InfoContext implements the logur.LoggerContext interface.
+func (l *Logger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.current().InfoContext(ctx, msg, fields...)
+}
+
+// WarnContext implements the logur.LoggerContext interface.
+func (l *Logger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.current().WarnContext(ctx, msg, fields...)
+}
+
+// ErrorContext implements the logur.LoggerContext interface.
+func (l *Logger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.current().ErrorContext(ctx, msg, fields...)
+}
+
+// LevelEnabled implements the logur.LevelEnabler interface.
+func (l *Logger) LevelEnabled(level logur.Level) bool {
+	return l.levels.LevelEnabled(level)
+}
+
+func newLogrusLogger(config Config, w io.Writer) logur.LoggerFacade {
 	logger := logrus.New()
 
-	logger.SetOutput(os.Stdout)
+	logger.SetOutput(w)
 	logger.SetFormatter(&logrus.TextFormatter{
 		DisableColors:             config.NoColor,
 		EnvironmentOverrideColors: true,
@@ -25,11 +173,19 @@ func NewLogger(config Config) logur.LoggerFacade {
 
 	case "json":
 		logger.SetFormatter(&logrus.JSONFormatter{})
-	}
 
-	if level, err := logrus.ParseLevel(config.Level); err == nil {
-		logger.SetLevel(level)
+	case "console":
+		logger.SetFormatter(&logrus.TextFormatter{
+			ForceColors:               !config.NoColor,
+			DisableColors:             config.NoColor,
+			EnvironmentOverrideColors: true,
+			FullTimestamp:             true,
+			TimestampFormat:           "15:04:05.000",
+		})
 	}
 
+	// Leave level filtering to the level controller
+	logger.SetLevel(logrus.TraceLevel)
+
 	return logrusadapter.New(logger)
 }
@@ -0,0 +1,91 @@
+//go:build go1.21
+// +build go1.21
+
+package log
+
+import (
+	"context"
+	"io"
+	"log/slog"
+
+	"logur.dev/logur"
+)
+
+// slogLevelTrace is a custom slog level below debug as slog does not have a trace level.
+const slogLevelTrace = slog.LevelDebug - 4
+
+func newSlogLogger(config Config, w io.Writer) (logur.LoggerFacade, error) {
+	// Leave level filtering to the level controller
+	options := &slog.HandlerOptions{Level: slogLevelTrace}
+
+	var handler slog.Handler
+
+	switch config.Format {
+	case "json":
+		handler = slog.NewJSONHandler(w, options)
+
+	default:
+		handler = slog.NewTextHandler(w, options)
+	}
+
+	return slogLogger{logger: slog.New(handler)}, nil
+}
+
+// slogLogger is a Logur adapter for the standard library structured logger.
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l slogLogger) Trace(msg string, fields ...map[string]interface{}) {
+	l.log(context.Background(), slogLevelTrace, msg, fields)
+}
+
+func (l slogLogger) Debug(msg string, fields ...map[string]interface{}) {
+	l.log(context.Background(), slog.LevelDebug, msg, fields)
+}
+
+func (l slogLogger) Info(msg string, fields ...map[string]interface{}) {
+	l.log(context.Background(), slog.LevelInfo, msg, fields)
+}
+
+func (l slogLogger) Warn(msg string, fields ...map[string]interface{}) {
+	l.log(context.Background(), slog.LevelWarn, msg, fields)
+}
+
+func (l slogLogger) Error(msg string, fields ...map[string]interface{}) {
+	l.log(context.Background(), slog.LevelError, msg, fields)
+}
+
+func (l slogLogger) TraceContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, slogLevelTrace, msg, fields)
+}
+
+func (l slogLogger) DebugContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, slog.LevelDebug, msg, fields)
+}
+
+func (l slogLogger) InfoContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, slog.LevelInfo, msg, fields)
+}
+
+func (l slogLogger) WarnContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, slog.LevelWarn, msg, fields)
+}
+
+func (l slogLogger) ErrorContext(ctx context.Context, msg string, fields ...map[string]interface{}) {
+	l.log(ctx, slog.LevelError, msg, fields)
+}
+
+func (l slogLogger) log(ctx context.Context, level slog.Level, msg string, fields []map[string]interface{}) {
+	var attrs []slog.Attr
+
+	if len(fields) > 0 {
+		attrs = make([]slog.Attr, 0, len(fields[0]))
+
+		for key, value := range fields[0] {
+			attrs = append(attrs, slog.Any(key, value))
+		}
+	}
+
+	l.logger.LogAttrs(ctx, level, msg, attrs...)
+}
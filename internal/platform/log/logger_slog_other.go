@@ -0,0 +1,15 @@
+//go:build !go1.21
+// +build !go1.21
+
+package log
+
+import (
+	"io"
+
+	"emperror.dev/errors"
+	"logur.dev/logur"
+)
+
+func newSlogLogger(_ Config, _ io.Writer) (logur.LoggerFacade, error) {
+	return nil, errors.New("slog log backend requires Go 1.21 or later")
+}
@@ -0,0 +1,179 @@
+package log
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLogger(t *testing.T) {
+	for _, backend := range []string{"logrus", "zap", "zerolog", "slog"} {
+		for _, format := range []string{"json", "logfmt", "console"} {
+			backend, format := backend, format
+
+			t.Run(backend+"/"+format, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "app.log")
+
+				config := Config{
+					Backend: backend,
+					Format:  format,
+					Level:   "info",
+					NoColor: true,
+					Outputs: []OutputConfig{
+						{
+							Type: "file",
+							Path: path,
+						},
+					},
+				}
+
+				logger, err := NewLogger(config, NewLevelController(config))
+				require.NoError(t, err)
+
+				logger.Debug("debug message")
+				logger.Info("info message", map[string]interface{}{"key": "value"})
+
+				output, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+
+				assert.Contains(t, string(output), "info message")
+				assert.Contains(t, string(output), "value")
+				assert.NotContains(t, string(output), "debug message")
+			})
+		}
+	}
+}
+
+func TestNewLogger_TraceLevel(t *testing.T) {
+	// Trace messages are logged on a separate level (instead of being folded into debug)
+	tests := map[string]struct {
+		trace string
+		debug string
+	}{
+		"logrus":  {"trace", "debug"},
+		"zap":     {"trace", "debug"},
+		"zerolog": {"trace", "debug"},
+		"slog":    {"DEBUG-4", "DEBUG"},
+	}
+
+	for backend, test := range tests {
+		for _, level := range []string{"trace", "debug"} {
+			backend, test, level := backend, test, level
+
+			t.Run(backend+"/"+level, func(t *testing.T) {
+				path := filepath.Join(t.TempDir(), "app.log")
+
+				config := Config{
+					Backend: backend,
+					Format:  "json",
+					Level:   level,
+					Outputs: []OutputConfig{
+						{
+							Type: "file",
+							Path: path,
+						},
+					},
+				}
+
+				logger, err := NewLogger(config, NewLevelController(config))
+				require.NoError(t, err)
+
+				logger.Trace("trace message")
+				logger.Debug("debug message")
+
+				output, err := ioutil.ReadFile(path)
+				require.NoError(t, err)
+
+				levels := make(map[string]interface{})
+
+				for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+					var entry map[string]interface{}
+
+					require.NoError(t, json.Unmarshal([]byte(line), &entry))
+
+					for _, key := range []string{"msg", "message"} {
+						if msg, ok := entry[key].(string); ok {
+							levels[msg] = entry["level"]
+						}
+					}
+				}
+
+				assert.Equal(t, test.debug, levels["debug message"])
+
+				if level == "trace" {
+					assert.Equal(t, test.trace, levels["trace message"])
+				} else {
+					assert.NotContains(t, levels, "trace message")
+				}
+			})
+		}
+	}
+}
+
+func TestLogger_SetFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	config := Config{
+		Format:  "logfmt",
+		Level:   "info",
+		NoColor: true,
+		Outputs: []OutputConfig{
+			{
+				Type: "file",
+				Path: path,
+			},
+		},
+	}
+
+	logger, err := NewLogger(config, NewLevelController(config))
+	require.NoError(t, err)
+
+	logger.Info("logfmt message")
+
+	require.NoError(t, logger.SetFormat("json", true))
+
+	logger.Info("json message")
+
+	assert.Error(t, logger.SetFormat("xml", true))
+
+	output, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(output), `msg="logfmt message"`)
+	assert.Contains(t, string(output), `"msg":"json message"`)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]Config{
+		"log backend must be logrus, zap, zerolog or slog": {
+			Backend: "log15",
+		},
+		"log format must be json, logfmt or console": {
+			Format: "xml",
+		},
+		"log level must be trace, debug, info, warn or error": {
+			Level: "verbose",
+		},
+		"log file path is required": {
+			Outputs: []OutputConfig{{Type: "file"}},
+		},
+		"log output type must be stdout, stderr, file or syslog": {
+			Outputs: []OutputConfig{{Type: "kafka"}},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.Validate()
+
+			assert.EqualError(t, err, name)
+		})
+	}
+}
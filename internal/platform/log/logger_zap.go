@@ -0,0 +1,95 @@
+package log
+
+import (
+	"context"
+	"io"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	zapadapter "logur.dev/adapter/zap"
+	"logur.dev/logur"
+)
+
+// zapLevelTrace is a custom zap level below debug as zap does not have a trace level.
+const zapLevelTrace = zapcore.DebugLevel - 1
+
+func newZapLogger(config Config, w io.Writer) logur.LoggerFacade {
+	var encoder zapcore.Encoder
+
+	switch config.Format {
+	case "json":
+		encoderConfig := zap.NewProductionEncoderConfig()
+		encoderConfig.EncodeLevel = zapTraceLevelEncoder(encoderConfig.EncodeLevel, "trace")
+
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+
+	default:
+		encoderConfig := zap.NewDevelopmentEncoderConfig()
+		if !config.NoColor {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+
+		encoderConfig.EncodeLevel = zapTraceLevelEncoder(encoderConfig.EncodeLevel, "TRACE")
+
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	}
+
+	// Leave level filtering to the level controller
+	logger := zap.New(zapcore.NewCore(encoder, zapcore.AddSync(w), zapLevelTrace))
+
+	return zapLogger{
+		Logger: zapadapter.New(logger),
+		logger: logger,
+	}
+}
+
+// zapTraceLevelEncoder encodes the custom trace level with a name (instead of its number).
+func zapTraceLevelEncoder(encode zapcore.LevelEncoder, name string) zapcore.LevelEncoder {
+	return func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		if level == zapLevelTrace {
+			enc.AppendString(name)
+
+			return
+		}
+
+		encode(level, enc)
+	}
+}
+
+// zapLogger logs trace messages on the custom trace level (the adapter logs them as debug).
+type zapLogger struct {
+	*zapadapter.Logger
+
+	logger *zap.Logger
+}
+
+func (l zapLogger) Trace(msg string, fields ...map[string]interface{}) {
+	ce := l.logger.Check(zapLevelTrace, msg)
+	if ce == nil {
+		return
+	}
+
+	var zapFields []zap.Field
+
+	if len(fields) > 0 {
+		zapFields = make([]zap.Field, 0, len(fields[0]))
+
+		for key, value := range fields[0] {
+			zapFields = append(zapFields, zap.Any(key, value))
+		}
+	}
+
+	ce.Write(zapFields...)
+}
+
+func (l zapLogger) TraceContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.Trace(msg, fields...)
+}
+
+func (l zapLogger) LevelEnabled(level logur.Level) bool {
+	if level == logur.Trace {
+		return l.logger.Core().Enabled(zapLevelTrace)
+	}
+
+	return l.Logger.LevelEnabled(level)
+}
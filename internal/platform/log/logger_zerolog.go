@@ -0,0 +1,48 @@
+package log
+
+import (
+	"context"
+	"io"
+
+	"github.com/rs/zerolog"
+	zerologadapter "logur.dev/adapter/zerolog"
+	"logur.dev/logur"
+)
+
+func newZerologLogger(config Config, w io.Writer) logur.LoggerFacade {
+	if config.Format != "json" {
+		w = zerolog.ConsoleWriter{
+			Out:     w,
+			NoColor: config.NoColor,
+		}
+	}
+
+	// Leave level filtering to the level controller
+	logger := zerolog.New(w).Level(zerolog.TraceLevel).With().Timestamp().Logger()
+
+	return zerologLogger{
+		Logger: zerologadapter.New(logger),
+		logger: logger,
+	}
+}
+
+// zerologLogger logs trace messages on the trace level (the adapter logs them as debug).
+type zerologLogger struct {
+	*zerologadapter.Logger
+
+	logger zerolog.Logger
+}
+
+func (l zerologLogger) Trace(msg string, fields ...map[string]interface{}) {
+	event := l.logger.Trace()
+
+	if len(fields) > 0 {
+		event.Fields(fields[0])
+	}
+
+	event.Msg(msg)
+}
+
+func (l zerologLogger) TraceContext(_ context.Context, msg string, fields ...map[string]interface{}) {
+	l.Trace(msg, fields...)
+}
@@ -0,0 +1,150 @@
+package log
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"emperror.dev/errors"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// output fans out log events to every configured destination.
+type output struct {
+	io.Writer
+
+	closers []io.Closer
+}
+
+// Close closes every destination (eg. log files).
+func (o output) Close() error {
+	var errs []error
+
+	for _, closer := range o.closers {
+		errs = append(errs, closer.Close())
+	}
+
+	return errors.Combine(errs...)
+}
+
+// newOutput creates a writer fanning out log events to every configured destination.
+func newOutput(configs []OutputConfig) (io.WriteCloser, error) {
+	if len(configs) == 0 {
+		return output{Writer: os.Stdout}, nil
+	}
+
+	var o output
+
+	writers := make([]io.Writer, 0, len(configs))
+
+	for _, config := range configs {
+		w, err := newOutputWriter(config)
+		if err != nil {
+			_ = o.Close()
+
+			return nil, err
+		}
+
+		writers = append(writers, w)
+
+		if closer, ok := w.(io.Closer); ok {
+			o.closers = append(o.closers, closer)
+		}
+	}
+
+	o.Writer = writers[0]
+	if len(writers) > 1 {
+		o.Writer = io.MultiWriter(writers...)
+	}
+
+	return o, nil
+}
+
+func newOutputWriter(config OutputConfig) (io.Writer, error) {
+	switch config.Type {
+	case "stdout":
+		return os.Stdout, nil
+
+	case "stderr":
+		return os.Stderr, nil
+
+	case "file":
+		return newFileOutput(config), nil
+
+	case "syslog":
+		w, err := newSyslogOutput(config)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to connect to syslog")
+		}
+
+		return w, nil
+	}
+
+	return nil, errors.Errorf("unknown log output type %q", config.Type)
+}
+
+// fileOutput is a log file rotated by size and (optionally) periodically.
+type fileOutput struct {
+	*lumberjack.Logger
+
+	ticker *time.Ticker
+	done   chan struct{}
+}
+
+// newFileOutput creates a log file writer rotating files by size and (optionally) time.
+func newFileOutput(config OutputConfig) *fileOutput {
+	w := &fileOutput{
+		Logger: &lumberjack.Logger{
+			Filename:   config.Path,
+			MaxSize:    config.MaxSize,
+			MaxAge:     maxAgeDays(config.MaxAge),
+			MaxBackups: config.MaxBackups,
+			LocalTime:  true,
+			Compress:   config.Compress,
+		},
+	}
+
+	if config.RotationInterval > 0 {
+		w.ticker = time.NewTicker(config.RotationInterval)
+		w.done = make(chan struct{})
+
+		go w.rotate()
+	}
+
+	return w
+}
+
+func (w *fileOutput) rotate() {
+	for {
+		select {
+		case <-w.ticker.C:
+			_ = w.Rotate()
+
+		case <-w.done:
+			return
+		}
+	}
+}
+
+// Close stops the periodic rotation and closes the log file.
+func (w *fileOutput) Close() error {
+	if w.ticker != nil {
+		w.ticker.Stop()
+		close(w.done)
+	}
+
+	return w.Logger.Close()
+}
+
+// maxAgeDays returns the maximum age of rotated log files in days (the unit of lumberjack).
+// Partial days are rounded up, so that files are never removed earlier than configured
+// (a zero value would keep them forever).
+func maxAgeDays(maxAge time.Duration) int {
+	if maxAge <= 0 {
+		return 0
+	}
+
+	const day = 24 * time.Hour
+
+	return int((maxAge + day - 1) / day)
+}
@@ -0,0 +1,18 @@
+//go:build !windows && !plan9
+// +build !windows,!plan9
+
+package log
+
+import (
+	"io"
+	"log/syslog"
+)
+
+func newSyslogOutput(config OutputConfig) (io.WriteCloser, error) {
+	w, err := syslog.Dial(config.Network, config.Address, syslog.LOG_INFO|syslog.LOG_DAEMON, config.Tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return w, nil
+}
@@ -0,0 +1,14 @@
+//go:build windows || plan9
+// +build windows plan9
+
+package log
+
+import (
+	"io"
+
+	"emperror.dev/errors"
+)
+
+func newSyslogOutput(_ OutputConfig) (io.WriteCloser, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,51 @@
+package log
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxAgeDays(t *testing.T) {
+	tests := map[time.Duration]int{
+		0:                   0,
+		-time.Hour:          0,
+		time.Hour:           1,
+		24 * time.Hour:      1,
+		25 * time.Hour:      2,
+		7 * 24 * time.Hour:  7,
+		30*24*time.Hour + 1: 31,
+	}
+
+	for maxAge, days := range tests {
+		assert.Equal(t, days, maxAgeDays(maxAge), maxAge.String())
+	}
+}
+
+func TestFileOutput_Close(t *testing.T) {
+	dir := t.TempDir()
+
+	w := newFileOutput(OutputConfig{
+		Path:             filepath.Join(dir, "app.log"),
+		RotationInterval: 10 * time.Millisecond,
+	})
+
+	_, err := w.Write([]byte("message\n"))
+	require.NoError(t, err)
+
+	require.NoError(t, w.Close())
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+
+	// Rotations would create new files if the output was still running
+	time.Sleep(50 * time.Millisecond)
+
+	filesAfterClose, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, filesAfterClose, len(files))
+}
@@ -0,0 +1,185 @@
+// Package multiplex serves HTTP and gRPC on a single listener.
+package multiplex
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+
+	"emperror.dev/errors"
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"github.com/soheilhy/cmux"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+)
+
+// Listener splits connections of a listener between a gRPC and an HTTP server.
+//
+// HTTP/2 connections sending gRPC requests are passed to the gRPC listener,
+// every other connection (HTTP/1.1, HTTP/2 cleartext and gRPC-Web) is passed to the HTTP listener.
+type Listener struct {
+	root    net.Listener
+	mux     cmux.CMux
+	matcher *grpcMatcher
+
+	grpc *childListener
+	http *childListener
+}
+
+// NewListener returns a new Listener.
+//
+// TLS (if any) should be terminated by the root listener.
+func NewListener(root net.Listener) *Listener {
+	grpcMatcher := newGRPCMatcher()
+	mux := cmux.New(grpcMatcher.Track(root))
+
+	return &Listener{
+		root:    root,
+		mux:     mux,
+		matcher: grpcMatcher,
+
+		grpc: newChildListener(mux.MatchWithWriters(grpcMatcher.Match)),
+		http: newChildListener(grpcMatcher.Listener(mux.Match(cmux.Any()))),
+	}
+}
+
+// childListener is a listener returned by the multiplexer.
+//
+// Closing it stops accepting connections for its server only: the root listener is closed by the Listener,
+// so that one server shutting down does not affect the other one.
+type childListener struct {
+	net.Listener
+
+	conns chan net.Conn
+	err   error
+
+	done chan struct{}
+	once sync.Once
+}
+
+func newChildListener(ln net.Listener) *childListener {
+	l := &childListener{
+		Listener: ln,
+		conns:    make(chan net.Conn),
+		done:     make(chan struct{}),
+	}
+
+	go l.run()
+
+	return l
+}
+
+// run accepts connections from the multiplexer until the root listener or the child listener is closed.
+func (l *childListener) run() {
+	for {
+		conn, err := l.Listener.Accept()
+		if err != nil {
+			l.err = err
+			close(l.conns)
+
+			return
+		}
+
+		select {
+		case l.conns <- conn:
+
+		case <-l.done:
+			_ = conn.Close()
+
+			return
+		}
+	}
+}
+
+func (l *childListener) Accept() (net.Conn, error) {
+	select {
+	case conn, ok := <-l.conns:
+		if !ok {
+			return nil, l.err
+		}
+
+		return conn, nil
+
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *childListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+
+	return nil
+}
+
+// GRPC returns the listener of the gRPC server.
+func (l *Listener) GRPC() net.Listener {
+	return l.grpc
+}
+
+// HTTP returns the listener of the HTTP server.
+func (l *Listener) HTTP() net.Listener {
+	return l.http
+}
+
+// Serve accepts connections on the root listener until it is closed.
+func (l *Listener) Serve() error {
+	err := l.mux.Serve()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+
+	return errors.WrapIf(err, "failed to accept connection")
+}
+
+// Drain waits for the gRPC and the HTTP servers to close their listeners (ie. to stop serving),
+// then closes the root listener.
+//
+// Closing the root listener while a server is still serving would make it fail instead of shutting down gracefully.
+func (l *Listener) Drain(ctx context.Context) error {
+	for _, child := range []*childListener{l.grpc, l.http} {
+		select {
+		case <-child.done:
+
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return l.Close()
+}
+
+// Close stops accepting connections.
+// Connections already passed to the servers are not affected.
+func (l *Listener) Close() error {
+	l.mux.Close()
+
+	err := l.root.Close()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+
+	return errors.WrapIf(err, "failed to close listener")
+}
+
+// Handler returns an HTTP handler serving gRPC-Web requests with the gRPC server
+// and every other request (including HTTP/2 cleartext ones) with the HTTP handler.
+//
+// allowOrigin decides which origins are allowed to make cross-origin gRPC-Web requests.
+func Handler(grpcServer *grpc.Server, handler http.Handler, allowOrigin func(origin string) bool) http.Handler {
+	grpcWebServer := grpcweb.WrapServer(grpcServer, grpcweb.WithOriginFunc(allowOrigin))
+
+	return h2c.NewHandler(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if grpcWebServer.IsGrpcWebRequest(r) || grpcWebServer.IsAcceptableGrpcCorsRequest(r) {
+				grpcWebServer.ServeHTTP(w, r)
+
+				return
+			}
+
+			handler.ServeHTTP(w, r)
+		}),
+		&http2.Server{},
+	)
+}
@@ -0,0 +1,262 @@
+package multiplex
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/hpack"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	muxLn := NewListener(ln)
+
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+
+	httpServer := &http.Server{
+		Handler: Handler(
+			grpcServer,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(r.Proto))
+			}),
+			func(string) bool { return true },
+		),
+	}
+
+	go func() { _ = muxLn.Serve() }()
+	go func() { _ = httpServer.Serve(muxLn.HTTP()) }()
+	go func() { _ = grpcServer.Serve(muxLn.GRPC()) }()
+
+	defer grpcServer.Stop()
+	defer httpServer.Close()
+	defer muxLn.Close()
+
+	url := "http://" + ln.Addr().String()
+
+	t.Run("HTTP/1.1", func(t *testing.T) {
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "HTTP/1.1", string(body))
+	})
+
+	t.Run("HTTP/2", func(t *testing.T) {
+		var dials int
+
+		client := &http.Client{
+			Transport: &http2.Transport{
+				AllowHTTP: true,
+				DialTLS: func(network string, addr string, _ *tls.Config) (net.Conn, error) {
+					dials++
+
+					return net.Dial(network, addr)
+				},
+			},
+		}
+
+		for i := 0; i < 2; i++ {
+			resp, err := client.Get(url)
+			require.NoError(t, err)
+
+			body, err := ioutil.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			_ = resp.Body.Close()
+
+			assert.Equal(t, "HTTP/2.0", string(body))
+		}
+
+		// The connection should survive the first request
+		assert.Equal(t, 1, dials)
+	})
+
+	t.Run("gRPC", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		conn, err := grpc.DialContext(ctx, ln.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+		require.NoError(t, err)
+		defer conn.Close()
+
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	})
+
+	t.Run("gRPC-Web", func(t *testing.T) {
+		message, err := proto.Marshal(&healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+
+		frame := make([]byte, 5, 5+len(message))
+		binary.BigEndian.PutUint32(frame[1:], uint32(len(message)))
+		frame = append(frame, message...)
+
+		resp, err := http.Post(url+"/grpc.health.v1.Health/Check", "application/grpc-web+proto", bytes.NewReader(frame))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := ioutil.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Greater(t, len(body), 5)
+
+		var healthResp healthpb.HealthCheckResponse
+
+		length := binary.BigEndian.Uint32(body[1:5])
+		require.NoError(t, proto.Unmarshal(body[5:5+length], &healthResp))
+
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, healthResp.GetStatus())
+	})
+}
+
+func TestListener_Drain(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	muxLn := NewListener(ln)
+
+	grpcServer := grpc.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, health.NewServer())
+
+	httpServer := &http.Server{Handler: http.NotFoundHandler()}
+
+	serveErrs := make(chan error, 3)
+
+	go func() { serveErrs <- muxLn.Serve() }()
+	go func() { serveErrs <- grpcServer.Serve(muxLn.GRPC()) }()
+	go func() {
+		err := httpServer.Serve(muxLn.HTTP())
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+
+		serveErrs <- err
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	drained := make(chan error, 1)
+
+	go func() { drained <- muxLn.Drain(ctx) }()
+
+	require.NoError(t, httpServer.Shutdown(ctx))
+
+	// The gRPC server keeps serving on the shared port until it is stopped
+	conn, err := grpc.DialContext(ctx, ln.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+	require.NoError(t, err)
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	grpcServer.GracefulStop()
+
+	require.NoError(t, <-drained)
+
+	// Servers shut down without errors
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, <-serveErrs)
+	}
+
+	_, err = net.Dial("tcp", ln.Addr().String())
+	assert.Error(t, err)
+}
+
+func TestListener_ConnectionDroppedBeforeAccept(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	muxLn := NewListener(ln)
+
+	served := make(chan error, 1)
+
+	go func() { served <- muxLn.Serve() }()
+
+	// The HTTP server stopped serving, so HTTP connections are never accepted
+	require.NoError(t, muxLn.HTTP().Close())
+
+	for i := 0; i < 2; i++ {
+		conn := dialHTTP2(t, ln.Addr().String())
+		defer conn.Close()
+	}
+
+	// The first connection is closed by the HTTP listener, the second one waits to be accepted
+	assert.Eventually(t, func() bool { return settingsSentCount(muxLn.matcher) == 1 }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, muxLn.Close())
+	require.NoError(t, <-served)
+
+	assert.Equal(t, 0, settingsSentCount(muxLn.matcher), "dropped connections should be forgotten")
+}
+
+// dialHTTP2 opens a cleartext HTTP/2 connection and sends a (non-gRPC) request.
+func dialHTTP2(t *testing.T, addr string) net.Conn {
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+
+	_, err = conn.Write([]byte(http2.ClientPreface))
+	require.NoError(t, err)
+
+	framer := http2.NewFramer(conn, conn)
+	require.NoError(t, framer.WriteSettings())
+
+	var headers bytes.Buffer
+
+	encoder := hpack.NewEncoder(&headers)
+
+	for _, field := range [][2]string{
+		{":method", "GET"},
+		{":scheme", "http"},
+		{":authority", addr},
+		{":path", "/"},
+		{"content-type", "text/plain"},
+	} {
+		require.NoError(t, encoder.WriteField(hpack.HeaderField{Name: field[0], Value: field[1]}))
+	}
+
+	require.NoError(t, framer.WriteHeaders(http2.HeadersFrameParam{
+		StreamID:      1,
+		BlockFragment: headers.Bytes(),
+		EndStream:     true,
+		EndHeaders:    true,
+	}))
+
+	return conn
+}
+
+func settingsSentCount(m *grpcMatcher) int {
+	var count int
+
+	m.settingsSent.Range(func(_, _ interface{}) bool {
+		count++
+
+		return true
+	})
+
+	return count
+}
@@ -0,0 +1,199 @@
+package multiplex
+
+import (
+	"io"
+	"net"
+	"sync"
+
+	"github.com/soheilhy/cmux"
+	"golang.org/x/net/http2"
+)
+
+// grpcMatcher matches gRPC connections.
+//
+// gRPC clients (eg. grpc-go) wait for the server settings frame before sending any headers,
+// so the matcher sends one to every HTTP/2 connection.
+// HTTP/2 clients acknowledge that frame, which the HTTP server would treat as a protocol error,
+// so connections that received it are remembered and the acknowledgement is dropped
+// before passing them to the HTTP server.
+//
+// Connections closed before reaching the HTTP server are forgotten (see Track).
+type grpcMatcher struct {
+	match        cmux.MatchWriter
+	settingsSent sync.Map // net.Conn -> struct{}
+}
+
+func newGRPCMatcher() *grpcMatcher {
+	return &grpcMatcher{
+		match: cmux.HTTP2MatchHeaderFieldSendSettings("content-type", "application/grpc"),
+	}
+}
+
+func (m *grpcMatcher) Match(w io.Writer, r io.Reader) bool {
+	recorder := &writeRecorder{Writer: w}
+
+	if m.match(recorder, r) {
+		return true
+	}
+
+	if recorder.written {
+		m.settingsSent.Store(w, struct{}{})
+	}
+
+	return false
+}
+
+// Track wraps the root listener, so that connections are forgotten when they are closed
+// (eg. when they are dropped by the multiplexer before the HTTP server accepts them).
+func (m *grpcMatcher) Track(ln net.Listener) net.Listener {
+	return &trackingListener{
+		Listener: ln,
+		matcher:  m,
+	}
+}
+
+// Listener wraps a listener and drops the settings acknowledgement from connections that received settings from the matcher.
+func (m *grpcMatcher) Listener(ln net.Listener) net.Listener {
+	return &settingsAckListener{
+		Listener: ln,
+		matcher:  m,
+	}
+}
+
+type trackingListener struct {
+	net.Listener
+
+	matcher *grpcMatcher
+}
+
+func (l *trackingListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &trackedConn{Conn: conn, matcher: l.matcher}, nil
+}
+
+type trackedConn struct {
+	net.Conn
+
+	matcher *grpcMatcher
+}
+
+func (c *trackedConn) Close() error {
+	c.matcher.settingsSent.Delete(c)
+
+	return c.Conn.Close()
+}
+
+type writeRecorder struct {
+	io.Writer
+
+	written bool
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.written = true
+
+	return w.Writer.Write(p)
+}
+
+type settingsAckListener struct {
+	net.Listener
+
+	matcher *grpcMatcher
+}
+
+func (l *settingsAckListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	muxConn, ok := conn.(*cmux.MuxConn)
+	if !ok {
+		return conn, nil
+	}
+
+	if _, sent := l.matcher.settingsSent.LoadAndDelete(muxConn.Conn); !sent {
+		return conn, nil
+	}
+
+	return &settingsAckConn{Conn: conn}, nil
+}
+
+// settingsAckConn drops the first settings acknowledgement frame read from an HTTP/2 connection.
+type settingsAckConn struct {
+	net.Conn
+
+	prefaceRead bool
+	dropped     bool
+
+	// pending holds data read, but not returned yet
+	pending []byte
+
+	// payload is the number of bytes remaining from the payload of the current frame
+	payload int
+}
+
+func (c *settingsAckConn) Read(p []byte) (int, error) {
+	for !c.dropped && len(c.pending) == 0 && c.payload == 0 {
+		if err := c.readNext(); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(c.pending) > 0 {
+		n := copy(p, c.pending)
+		c.pending = c.pending[n:]
+
+		return n, nil
+	}
+
+	if c.payload > 0 && len(p) > c.payload {
+		p = p[:c.payload]
+	}
+
+	n, err := c.Conn.Read(p)
+
+	if c.payload > 0 {
+		c.payload -= n
+	}
+
+	return n, err
+}
+
+func (c *settingsAckConn) readNext() error {
+	if !c.prefaceRead {
+		c.pending = make([]byte, len(http2.ClientPreface))
+
+		if _, err := io.ReadFull(c.Conn, c.pending); err != nil {
+			return err
+		}
+
+		c.prefaceRead = true
+
+		return nil
+	}
+
+	header := make([]byte, 9) // nolint: gomnd
+	if _, err := io.ReadFull(c.Conn, header); err != nil {
+		return err
+	}
+
+	length := int(header[0])<<16 | int(header[1])<<8 | int(header[2])
+	frameType := http2.FrameType(header[3])
+	flags := http2.Flags(header[4])
+
+	if frameType == http2.FrameSettings && flags.Has(http2.FlagSettingsAck) && length == 0 {
+		c.dropped = true
+
+		return nil
+	}
+
+	c.pending = header
+	c.payload = length
+
+	return nil
+}
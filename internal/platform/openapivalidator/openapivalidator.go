@@ -0,0 +1,133 @@
+// Package openapivalidator provides an HTTP middleware that validates requests against an OpenAPI 3 document.
+package openapivalidator
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"strings"
+
+	"emperror.dev/errors"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	kithttp "github.com/go-kit/kit/transport/http"
+)
+
+// Load loads an OpenAPI 3 document from a filesystem and validates it.
+func Load(fsys fs.FS, name string) (*openapi3.T, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to read OpenAPI document", "name", name)
+	}
+
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(data)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "failed to load OpenAPI document", "name", name)
+	}
+
+	err = doc.Validate(loader.Context)
+	if err != nil {
+		return nil, errors.WrapIfWithDetails(err, "invalid OpenAPI document", "name", name)
+	}
+
+	return doc, nil
+}
+
+// Middleware returns an HTTP middleware that validates requests against an OpenAPI 3 document.
+//
+// Requests the document does not describe are passed to the next handler without validation.
+// Invalid requests are rejected: the error passed to the error encoder carries the BadRequest behavior
+// (see https://github.com/sagikazarmark/appkit), so problem converters can translate it to a 400 response.
+func Middleware(doc *openapi3.T, errorEncoder kithttp.ErrorEncoder) (func(http.Handler) http.Handler, error) {
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to create OpenAPI router")
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			err = openapi3filter.ValidateRequest(r.Context(), &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+			})
+			if err != nil {
+				errorEncoder(r.Context(), newValidationError(err), w)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}, nil
+}
+
+// validationError is returned when a request does not match the OpenAPI document.
+type validationError struct {
+	msg string
+	err error
+}
+
+func newValidationError(err error) error {
+	msg := "invalid request"
+
+	var requestErr *openapi3filter.RequestError
+	if errors.As(err, &requestErr) {
+		switch {
+		case requestErr.RequestBody != nil:
+			msg = "invalid request body"
+
+		case requestErr.Parameter != nil:
+			msg = fmt.Sprintf("invalid %s parameter %q", requestErr.Parameter.In, requestErr.Parameter.Name)
+		}
+	}
+
+	// Schema errors include the whole schema and value in their message by default
+	var schemaErr *openapi3.SchemaError
+	if errors.As(err, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+			msg += ": /" + strings.Join(pointer, "/")
+		}
+
+		return validationError{msg: msg + ": " + schemaErr.Reason, err: err}
+	}
+
+	if requestErr != nil {
+		reason := requestErr.Reason
+		if requestErr.Err != nil {
+			reason = requestErr.Err.Error()
+		}
+
+		return validationError{msg: msg + ": " + reason, err: err}
+	}
+
+	return validationError{msg: msg + ": " + err.Error(), err: err}
+}
+
+func (e validationError) Error() string {
+	return e.msg
+}
+
+func (e validationError) Unwrap() error {
+	return e.err
+}
+
+// BadRequest implements the appkit BadRequest behavior.
+func (validationError) BadRequest() bool {
+	return true
+}
+
+// ClientError implements the appkit ClientError behavior.
+func (validationError) ClientError() bool {
+	return true
+}
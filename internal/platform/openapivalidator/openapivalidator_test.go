@@ -0,0 +1,112 @@
+package openapivalidator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	appkithttp "github.com/sagikazarmark/appkit/transport/http"
+	kitxhttp "github.com/sagikazarmark/kitx/transport/http"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const document = `{
+  "openapi": "3.0.3",
+  "info": {"title": "Test", "version": "1.0.0"},
+  "paths": {
+    "/items": {
+      "post": {
+        "requestBody": {
+          "required": true,
+          "content": {
+            "application/json": {
+              "schema": {
+                "type": "object",
+                "properties": {"title": {"type": "string"}, "order": {"type": "integer"}},
+                "required": ["title"]
+              }
+            }
+          }
+        },
+        "responses": {"201": {"description": "Created"}}
+      }
+    }
+  }
+}`
+
+func TestMiddleware(t *testing.T) {
+	doc, err := Load(fstest.MapFS{"openapi.json": {Data: []byte(document)}}, "openapi.json")
+	require.NoError(t, err)
+
+	middleware, err := Middleware(doc, kitxhttp.NewJSONProblemErrorEncoder(appkithttp.NewDefaultProblemConverter()))
+	require.NoError(t, err)
+
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	do := func(method string, path string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		rec := do(http.MethodPost, "/items", `{"title":"Buy milk","order":1}`)
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("Undocumented", func(t *testing.T) {
+		rec := do(http.MethodGet, "/other", "")
+
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	tests := map[string]struct {
+		body   string
+		detail string
+	}{
+		"MissingBody": {
+			body:   "",
+			detail: "invalid request body: value is required but missing",
+		},
+		"MissingProperty": {
+			body:   `{"order":1}`,
+			detail: `invalid request body: /title: property "title" is missing`,
+		},
+		"InvalidProperty": {
+			body:   `{"title":"Buy milk","order":"first"}`,
+			detail: "invalid request body: /order: Field must be set to integer or not be present",
+		},
+		"Malformed": {
+			body:   `{"title":`,
+			detail: "invalid request body: unexpected EOF",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			rec := do(http.MethodPost, "/items", test.body)
+
+			assert.Equal(t, http.StatusBadRequest, rec.Code)
+			assert.Equal(t, "application/problem+json", rec.Header().Get("Content-Type"))
+
+			var problem map[string]interface{}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+
+			assert.Equal(t, float64(http.StatusBadRequest), problem["status"])
+			assert.Equal(t, test.detail, problem["detail"])
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package opencensus
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"contrib.go.opencensus.io/exporter/ocagent"
+	"emperror.dev/errors"
+	"github.com/AppsFlyer/go-sundheit/checks"
+)
+
+// NewExporterCheck returns a health check that fails when the OpenCensus agent is unreachable.
+func NewExporterCheck(config ExporterConfig, timeout time.Duration) checks.Check {
+	address := config.Address
+	if address == "" {
+		address = fmt.Sprintf("%s:%d", ocagent.DefaultAgentHost, ocagent.DefaultAgentPort)
+	}
+
+	return &checks.CustomCheck{
+		CheckName: "opencensus.exporter",
+		CheckFunc: func() (interface{}, error) {
+			conn, err := net.DialTimeout("tcp", address, timeout)
+			if err != nil {
+				return nil, errors.WrapIf(err, "opencensus agent is unreachable")
+			}
+
+			_ = conn.Close()
+
+			return nil, nil
+		},
+	}
+}
@@ -0,0 +1,170 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	kitgrpc "github.com/go-kit/kit/transport/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// APIKeyHeader is the HTTP header (and gRPC metadata key) carrying the API key of a client.
+//
+// API keys sent by clients are not trusted as is (a client could send a new key with every request):
+// authentication middleware should verify them and call ContextWithAPIKey.
+const APIKeyHeader = "X-Api-Key"
+
+// ForwardedForHeader is the HTTP header (and gRPC metadata key) carrying the addresses of a client and the proxies
+// a request passed through. It is only trusted when the request comes from a trusted proxy.
+const ForwardedForHeader = "X-Forwarded-For"
+
+// Client identifies the client making a request.
+type Client struct {
+	// IP is the IP address of the client.
+	IP string
+
+	// APIKey is the verified API key of the client (if any).
+	APIKey string
+
+	// Principal is the authenticated principal (if any).
+	Principal string
+}
+
+func (c Client) key(key string) string {
+	switch {
+	case key == KeyPrincipal && c.Principal != "":
+		return "principal:" + c.Principal
+
+	case (key == KeyPrincipal || key == KeyAPIKey) && c.APIKey != "":
+		return "apikey:" + c.APIKey
+
+	default:
+		return "ip:" + c.IP
+	}
+}
+
+type contextKey int
+
+const (
+	clientContextKey contextKey = iota
+	apiKeyContextKey
+	principalContextKey
+	headerContextKey
+)
+
+// ContextWithClient returns a new context with the client making the request.
+func ContextWithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientContextKey, client)
+}
+
+// ContextWithAPIKey returns a new context with the verified API key of the client making the request.
+// Authentication middleware should call it to let clients be identified by their API key.
+func ContextWithAPIKey(ctx context.Context, apiKey string) context.Context {
+	return context.WithValue(ctx, apiKeyContextKey, apiKey)
+}
+
+// ContextWithPrincipal returns a new context with the authenticated principal making the request.
+// Authentication middleware should call it to let clients be identified by their principal.
+func ContextWithPrincipal(ctx context.Context, principal string) context.Context {
+	return context.WithValue(ctx, principalContextKey, principal)
+}
+
+// ClientFromContext returns the client making the request from a context.
+func ClientFromContext(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientContextKey).(Client)
+
+	if apiKey, aok := ctx.Value(apiKeyContextKey).(string); aok {
+		client.APIKey = apiKey
+		ok = true
+	}
+
+	if principal, pok := ctx.Value(principalContextKey).(string); pok {
+		client.Principal = principal
+		ok = true
+	}
+
+	return client, ok
+}
+
+// HTTPMiddleware returns an HTTP middleware that identifies the client making a request
+// and lets the endpoint middleware set rate limit headers on the response.
+//
+// The address of the client is taken from the X-Forwarded-For header if the request comes from a trusted proxy.
+func HTTPMiddleware(limiter *Limiter) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := ContextWithClient(r.Context(), Client{
+				IP: limiter.clientIP(hostIP(r.RemoteAddr), r.Header.Values(ForwardedForHeader)),
+			})
+			ctx = context.WithValue(ctx, headerContextKey, &headerWriter{header: w.Header()})
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// GRPCToContext returns a go-kit gRPC server request function that identifies the client making a request.
+//
+// Clients already identified (eg. by HTTPMiddleware when the request comes through a gateway) are kept.
+// The address of the client is taken from the X-Forwarded-For metadata if the request comes from a trusted proxy.
+func GRPCToContext(limiter *Limiter) kitgrpc.ServerRequestFunc {
+	return func(ctx context.Context, md metadata.MD) context.Context {
+		if _, ok := ctx.Value(clientContextKey).(Client); ok {
+			return ctx
+		}
+
+		var remoteIP string
+
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			remoteIP = hostIP(p.Addr.String())
+		}
+
+		return ContextWithClient(ctx, Client{
+			IP: limiter.clientIP(remoteIP, md.Get(ForwardedForHeader)),
+		})
+	}
+}
+
+// clientIP returns the IP address of a client.
+//
+// Addresses in the X-Forwarded-For header are checked from the closest one (the last one):
+// the first address that is not a trusted proxy is the client.
+func (l *Limiter) clientIP(remoteIP string, forwardedFor []string) string {
+	if !l.trustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	var addrs []string
+
+	for _, value := range forwardedFor {
+		for _, addr := range strings.Split(value, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+
+	ip := remoteIP
+
+	for i := len(addrs) - 1; i >= 0; i-- {
+		ip = addrs[i]
+
+		if !l.trustedProxy(ip) {
+			break
+		}
+	}
+
+	return ip
+}
+
+func hostIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+
+	return host
+}
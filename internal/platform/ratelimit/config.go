@@ -0,0 +1,118 @@
+package ratelimit
+
+import (
+	"net"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+// Client identification methods.
+const (
+	// KeyIP identifies clients by their IP address.
+	KeyIP = "ip"
+
+	// KeyAPIKey identifies clients by their API key verified by authentication (falling back to their IP address).
+	KeyAPIKey = "apiKey"
+
+	// KeyPrincipal identifies clients by their authenticated principal
+	// (falling back to their verified API key, then to their IP address).
+	KeyPrincipal = "principal"
+)
+
+// Config configures rate limiting.
+type Config struct {
+	// Enabled enables rate limiting.
+	Enabled bool
+
+	// Key is the way clients are identified (ip, apiKey or principal).
+	Key string
+
+	// Limit is the number of requests per second a client can make.
+	Limit float64
+
+	// Burst is the maximum number of requests a client can make at once.
+	Burst int
+
+	// TrustedProxies lists the networks (CIDR notation or single IP addresses) of proxies in front of the application
+	// (eg. an ingress controller or a load balancer).
+	// The X-Forwarded-For header of requests coming from them identifies clients by their IP address.
+	TrustedProxies []string
+
+	// Operations configures different limits for individual operations (eg. todo.AddItem).
+	// Operations without a limit of their own share the default limit.
+	Operations []OperationConfig
+}
+
+// OperationConfig configures the rate limit of an operation.
+type OperationConfig struct {
+	// Name is the name of the operation (eg. todo.AddItem).
+	Name string
+
+	// Limit is the number of requests per second a client can make. Zero means no limit.
+	Limit float64
+
+	// Burst is the maximum number of requests a client can make at once.
+	Burst int
+}
+
+// Validate validates the configuration.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	switch c.Key {
+	case KeyIP, KeyAPIKey, KeyPrincipal:
+
+	default:
+		return errors.Errorf("unknown rate limit key %q (accepted values are: ip, apiKey, principal)", c.Key)
+	}
+
+	if c.Limit <= 0 {
+		return errors.New("rate limit must be positive")
+	}
+
+	if _, err := parseNetworks(c.TrustedProxies); err != nil {
+		return err
+	}
+
+	for _, operation := range c.Operations {
+		if operation.Name == "" {
+			return errors.New("rate limited operation name is required")
+		}
+
+		if operation.Limit < 0 {
+			return errors.Errorf("rate limit of operation %q must not be negative", operation.Name)
+		}
+	}
+
+	return nil
+}
+
+// parseNetworks parses networks in CIDR notation (single IP addresses are accepted as well).
+func parseNetworks(networks []string) ([]*net.IPNet, error) {
+	ipNets := make([]*net.IPNet, 0, len(networks))
+
+	for _, network := range networks {
+		if !strings.Contains(network, "/") {
+			ip := net.ParseIP(network)
+			if ip == nil {
+				return nil, errors.Errorf("invalid trusted proxy address %q", network)
+			}
+
+			ipNets = append(ipNets, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			return nil, errors.Errorf("invalid trusted proxy network %q", network)
+		}
+
+		ipNets = append(ipNets, ipNet)
+	}
+
+	return ipNets, nil
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		config Config
+		err    string
+	}{
+		"Disabled": {
+			config: Config{},
+		},
+		"Valid": {
+			config: Config{Enabled: true, Key: KeyIP, Limit: 10, Burst: 20},
+		},
+		"UnknownKey": {
+			config: Config{Enabled: true, Key: "cookie", Limit: 10},
+			err:    `unknown rate limit key "cookie" (accepted values are: ip, apiKey, principal)`,
+		},
+		"ZeroLimit": {
+			config: Config{Enabled: true, Key: KeyIP},
+			err:    "rate limit must be positive",
+		},
+		"TrustedProxies": {
+			config: Config{Enabled: true, Key: KeyIP, Limit: 10, TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1", "::1"}},
+		},
+		"InvalidTrustedProxyNetwork": {
+			config: Config{Enabled: true, Key: KeyIP, Limit: 10, TrustedProxies: []string{"10.0.0.0/33"}},
+			err:    `invalid trusted proxy network "10.0.0.0/33"`,
+		},
+		"InvalidTrustedProxyAddress": {
+			config: Config{Enabled: true, Key: KeyIP, Limit: 10, TrustedProxies: []string{"proxy"}},
+			err:    `invalid trusted proxy address "proxy"`,
+		},
+		"OperationWithoutName": {
+			config: Config{Enabled: true, Key: KeyIP, Limit: 10, Operations: []OperationConfig{{Limit: 1}}},
+			err:    "rate limited operation name is required",
+		},
+		"NegativeOperationLimit": {
+			config: Config{Enabled: true, Key: KeyIP, Limit: 10, Operations: []OperationConfig{{Name: "todo.AddItem", Limit: -1}}},
+			err:    `rate limit of operation "todo.AddItem" must not be negative`,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.config.Validate()
+
+			if test.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, test.err)
+			}
+		})
+	}
+}
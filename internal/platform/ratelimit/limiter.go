@@ -0,0 +1,201 @@
+// Package ratelimit limits the rate of requests made by clients using token buckets.
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// sweepInterval is the minimum time between removing idle buckets.
+const sweepInterval = time.Minute
+
+// Limiter keeps a token bucket for every client (and operation with a limit of its own).
+// Its configuration can be changed at runtime.
+type Limiter struct {
+	config         Config
+	operations     map[string]policy
+	trustedProxies []*net.IPNet
+
+	buckets   map[string]*bucket
+	lastSweep time.Time
+
+	now func() time.Time
+
+	mu sync.Mutex
+}
+
+// Result is the outcome of a request taking a token from a bucket.
+type Result struct {
+	// Allowed reports whether the request is allowed.
+	Allowed bool
+
+	// Limit is the size of the bucket.
+	Limit int
+
+	// Remaining is the number of requests the client can make right now.
+	Remaining int
+
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+
+	// RetryAfter is the time until the next request is allowed (if the request is not allowed).
+	RetryAfter time.Duration
+}
+
+type policy struct {
+	limit float64
+	burst int
+}
+
+type bucket struct {
+	policy
+
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter returns a new Limiter.
+func NewLimiter(config Config) *Limiter {
+	l := &Limiter{
+		now: time.Now,
+	}
+
+	l.SetConfig(config)
+
+	return l
+}
+
+// SetConfig changes the rate limit configuration.
+// Clients start with a full bucket after the configuration changes.
+func (l *Limiter) SetConfig(config Config) {
+	operations := make(map[string]policy, len(config.Operations))
+
+	for _, operation := range config.Operations {
+		operations[strings.ToLower(operation.Name)] = newPolicy(operation.Limit, operation.Burst)
+	}
+
+	// The configuration is validated beforehand
+	trustedProxies, _ := parseNetworks(config.TrustedProxies)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.config = config
+	l.operations = operations
+	l.trustedProxies = trustedProxies
+	l.buckets = make(map[string]*bucket)
+}
+
+// trustedProxy reports whether an IP address belongs to a trusted proxy.
+func (l *Limiter) trustedProxy(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, ipNet := range l.trustedProxies {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func newPolicy(limit float64, burst int) policy {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return policy{limit: limit, burst: burst}
+}
+
+// Take takes a token from the bucket of a client for an operation.
+// The second return value is false if the request is not rate limited
+// (because rate limiting is disabled or the operation has no limit).
+func (l *Limiter) Take(operation string, client Client) (Result, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.config.Enabled {
+		return Result{}, false
+	}
+
+	key := client.key(l.config.Key)
+	operation = strings.ToLower(operation)
+
+	p, ok := l.operations[operation]
+	if ok {
+		key = operation + "\x00" + key
+	} else {
+		p = newPolicy(l.config.Limit, l.config.Burst)
+	}
+
+	if p.limit <= 0 {
+		return Result{}, false
+	}
+
+	now := l.now()
+
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+	if !ok || b.policy != p {
+		b = &bucket{policy: p, tokens: float64(p.burst), last: now}
+		l.buckets[key] = b
+	}
+
+	return b.take(now), true
+}
+
+// sweep removes buckets that are full (so that they do not pile up for clients that are gone).
+func (l *Limiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	l.lastSweep = now
+
+	for key, b := range l.buckets {
+		if b.fill(now) >= float64(b.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// fill returns the number of tokens in the bucket at a given time.
+func (b *bucket) fill(now time.Time) float64 {
+	return math.Min(float64(b.burst), b.tokens+now.Sub(b.last).Seconds()*b.limit)
+}
+
+func (b *bucket) take(now time.Time) Result {
+	b.tokens = b.fill(now)
+	b.last = now
+
+	result := Result{
+		Limit: b.burst,
+	}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = b.duration(1 - b.tokens)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = b.duration(float64(b.burst) - b.tokens)
+
+	return result
+}
+
+// duration returns the time it takes to add a number of tokens to the bucket.
+func (b *bucket) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / b.limit * float64(time.Second))
+}
@@ -0,0 +1,192 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLimiter(config Config) (*Limiter, *time.Time) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	limiter := NewLimiter(config)
+	limiter.now = func() time.Time { return now }
+
+	return limiter, &now
+}
+
+func TestLimiter_Take(t *testing.T) {
+	limiter, now := newTestLimiter(Config{
+		Enabled: true,
+		Key:     KeyIP,
+		Limit:   1,
+		Burst:   2,
+	})
+
+	client := Client{IP: "127.0.0.1"}
+
+	result, limited := limiter.Take("todo.AddItem", client)
+	require.True(t, limited)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, result)
+
+	result, _ = limiter.Take("todo.ListItems", client)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second}, result)
+
+	result, _ = limiter.Take("todo.AddItem", client)
+	assert.Equal(t, Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 2 * time.Second, RetryAfter: time.Second}, result)
+
+	// Other clients have their own bucket
+	result, _ = limiter.Take("todo.AddItem", Client{IP: "127.0.0.2"})
+	assert.True(t, result.Allowed)
+
+	*now = now.Add(500 * time.Millisecond)
+
+	result, _ = limiter.Take("todo.AddItem", client)
+	assert.Equal(t, Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 1500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}, result) // nolint: lll
+
+	*now = now.Add(500 * time.Millisecond)
+
+	result, _ = limiter.Take("todo.AddItem", client)
+	assert.True(t, result.Allowed)
+}
+
+func TestLimiter_Take_Operations(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{
+		Enabled: true,
+		Key:     KeyIP,
+		Limit:   1,
+		Burst:   1,
+		Operations: []OperationConfig{
+			{Name: "todo.AddItem", Limit: 1, Burst: 3},
+			{Name: "todo.ListItems", Limit: 0},
+		},
+	})
+
+	client := Client{IP: "127.0.0.1"}
+
+	for i := 0; i < 3; i++ {
+		result, _ := limiter.Take("todo.AddItem", client)
+		assert.True(t, result.Allowed)
+	}
+
+	result, _ := limiter.Take("todo.AddItem", client)
+	assert.False(t, result.Allowed)
+
+	// Operations without a limit of their own share the default bucket
+	result, _ = limiter.Take("todo.GetItem", client)
+	assert.True(t, result.Allowed)
+
+	result, _ = limiter.Take("todo.DeleteItem", client)
+	assert.False(t, result.Allowed)
+
+	// Operations with zero limit are not limited
+	_, limited := limiter.Take("todo.ListItems", client)
+	assert.False(t, limited)
+}
+
+func TestLimiter_Take_Key(t *testing.T) {
+	tests := map[string]struct {
+		key   string
+		other Client
+		same  bool
+	}{
+		"IP": {
+			key:   KeyIP,
+			other: Client{IP: "127.0.0.1", APIKey: "other"},
+			same:  true,
+		},
+		"APIKey": {
+			key:   KeyAPIKey,
+			other: Client{IP: "127.0.0.1", APIKey: "other"},
+			same:  false,
+		},
+		"APIKeyFromOtherIP": {
+			key:   KeyAPIKey,
+			other: Client{IP: "127.0.0.2", APIKey: "key"},
+			same:  true,
+		},
+		"Principal": {
+			key:   KeyPrincipal,
+			other: Client{IP: "127.0.0.1", APIKey: "key", Principal: "other"},
+			same:  false,
+		},
+		"PrincipalFallbackToAPIKey": {
+			key:   KeyPrincipal,
+			other: Client{IP: "127.0.0.2", APIKey: "key"},
+			same:  true,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			limiter, _ := newTestLimiter(Config{Enabled: true, Key: test.key, Limit: 1, Burst: 1})
+
+			result, _ := limiter.Take("", Client{IP: "127.0.0.1", APIKey: "key"})
+			require.True(t, result.Allowed)
+
+			result, _ = limiter.Take("", test.other)
+			assert.Equal(t, !test.same, result.Allowed)
+		})
+	}
+}
+
+func TestLimiter_clientIP(t *testing.T) {
+	limiter := NewLimiter(Config{TrustedProxies: []string{"10.0.0.0/8", "2001:db8::1"}})
+
+	tests := map[string]struct {
+		remoteIP     string
+		forwardedFor []string
+		ip           string
+	}{
+		"NoProxy":               {"192.0.2.1", nil, "192.0.2.1"},
+		"UntrustedProxy":        {"192.0.2.1", []string{"192.0.2.2"}, "192.0.2.1"},
+		"TrustedProxy":          {"10.0.0.1", []string{"192.0.2.2"}, "192.0.2.2"},
+		"TrustedProxyIPv6":      {"2001:db8::1", []string{"192.0.2.2"}, "192.0.2.2"},
+		"TrustedProxyNoHeader":  {"10.0.0.1", nil, "10.0.0.1"},
+		"TrustedProxies":        {"10.0.0.1", []string{"192.0.2.2, 10.0.0.2"}, "192.0.2.2"},
+		"MultipleHeaders":       {"10.0.0.1", []string{"192.0.2.2", "10.0.0.2"}, "192.0.2.2"},
+		"SpoofedAddress":        {"10.0.0.1", []string{"198.51.100.1, 192.0.2.2"}, "192.0.2.2"},
+		"OnlyTrustedProxies":    {"10.0.0.1", []string{"10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		"EmptyAddressesSkipped": {"10.0.0.1", []string{"192.0.2.2, ,"}, "192.0.2.2"},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.ip, limiter.clientIP(test.remoteIP, test.forwardedFor))
+		})
+	}
+}
+
+func TestLimiter_SetConfig(t *testing.T) {
+	limiter, _ := newTestLimiter(Config{})
+
+	_, limited := limiter.Take("", Client{})
+	assert.False(t, limited)
+
+	limiter.SetConfig(Config{Enabled: true, Key: KeyIP, Limit: 1, Burst: 1})
+
+	result, limited := limiter.Take("", Client{})
+	assert.True(t, limited)
+	assert.True(t, result.Allowed)
+
+	result, _ = limiter.Take("", Client{})
+	assert.False(t, result.Allowed)
+}
+
+func TestLimiter_Sweep(t *testing.T) {
+	limiter, now := newTestLimiter(Config{Enabled: true, Key: KeyIP, Limit: 1, Burst: 1})
+
+	limiter.Take("", Client{IP: "127.0.0.1"})
+	assert.Len(t, limiter.buckets, 1)
+
+	*now = now.Add(sweepInterval)
+
+	limiter.Take("", Client{IP: "127.0.0.2"})
+	assert.Len(t, limiter.buckets, 1)
+}
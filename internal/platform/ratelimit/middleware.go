@@ -0,0 +1,146 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/go-kit/kit/endpoint"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Rate limit response headers (see https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/).
+const (
+	LimitHeader      = "RateLimit-Limit"
+	RemainingHeader  = "RateLimit-Remaining"
+	ResetHeader      = "RateLimit-Reset"
+	RetryAfterHeader = "Retry-After"
+)
+
+// Rate limit metrics
+// nolint: gochecknoglobals
+var (
+	operationKey, _ = tag.NewKey("operation")
+
+	ThrottledRequestCount = stats.Int64(
+		"throttled_request_count",
+		"Number of requests rejected by the rate limiter",
+		stats.UnitDimensionless,
+	)
+)
+
+// nolint: gochecknoglobals
+var (
+	ThrottledRequestCountView = &view.View{
+		Name:        "throttled_request_count",
+		Description: "Count of requests rejected by the rate limiter",
+		Measure:     ThrottledRequestCount,
+		TagKeys:     []tag.Key{operationKey},
+		Aggregation: view.Count(),
+	}
+)
+
+// Middleware returns an endpoint middleware that rate limits requests.
+//
+// Clients are identified using information put in the context by transport specific middleware
+// (see HTTPMiddleware and GRPCToContext).
+// Rate limit information is returned in HTTP headers or gRPC metadata.
+func Middleware(limiter *Limiter) endpoint.Middleware {
+	return func(next endpoint.Endpoint) endpoint.Endpoint {
+		return func(ctx context.Context, request interface{}) (interface{}, error) {
+			operation, _ := kitxendpoint.OperationName(ctx)
+			client, _ := ClientFromContext(ctx)
+
+			result, limited := limiter.Take(operation, client)
+			if !limited {
+				return next(ctx, request)
+			}
+
+			setHeaders(ctx, result)
+
+			if !result.Allowed {
+				_ = stats.RecordWithTags(ctx, []tag.Mutator{tag.Upsert(operationKey, operation)}, ThrottledRequestCount.M(1))
+
+				return nil, limitExceededError{}
+			}
+
+			return next(ctx, request)
+		}
+	}
+}
+
+func setHeaders(ctx context.Context, result Result) {
+	headers := map[string]string{
+		LimitHeader:     strconv.Itoa(result.Limit),
+		RemainingHeader: strconv.Itoa(result.Remaining),
+		ResetHeader:     seconds(result.Reset),
+	}
+
+	if !result.Allowed {
+		headers[RetryAfterHeader] = seconds(result.RetryAfter)
+	}
+
+	// HTTP requests (including the ones passed to the gRPC server by a gateway)
+	if w, ok := ctx.Value(headerContextKey).(*headerWriter); ok {
+		w.set(headers)
+
+		return
+	}
+
+	// gRPC requests (fails silently outside of gRPC calls)
+	_ = grpc.SetHeader(ctx, metadata.New(headers))
+}
+
+// seconds formats a duration as a number of seconds (rounded up).
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// headerWriter sets headers on an HTTP response.
+// GraphQL resolvers may call endpoints concurrently, so writes are synchronized.
+type headerWriter struct {
+	header http.Header
+	mu     sync.Mutex
+}
+
+func (w *headerWriter) set(headers map[string]string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for key, value := range headers {
+		w.header.Set(key, value)
+	}
+}
+
+// limitExceededError is returned when a client exceeds its rate limit.
+type limitExceededError struct{}
+
+func (limitExceededError) Error() string {
+	return "rate limit exceeded"
+}
+
+// GRPCStatus returns a ResourceExhausted gRPC status.
+func (e limitExceededError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, e.Error())
+}
+
+// ServiceError tells the error handler that the error is an expected one.
+func (limitExceededError) ServiceError() bool {
+	return true
+}
+
+// IsLimitExceededError reports whether an error was returned because a client exceeded its rate limit.
+func IsLimitExceededError(err error) bool {
+	return errors.As(err, &limitExceededError{})
+}
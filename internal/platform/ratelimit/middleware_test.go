@@ -0,0 +1,148 @@
+package ratelimit
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/kit/endpoint"
+	kitxendpoint "github.com/sagikazarmark/kitx/endpoint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func newTestEndpoint(limiter *Limiter) endpoint.Endpoint {
+	return endpoint.Chain(
+		kitxendpoint.OperationNameMiddleware("todo.AddItem"),
+		Middleware(limiter),
+	)(func(ctx context.Context, request interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+}
+
+func TestMiddleware_HTTP(t *testing.T) {
+	limiter := NewLimiter(Config{Enabled: true, Key: KeyAPIKey, Limit: 1, Burst: 1})
+	e := newTestEndpoint(limiter)
+
+	var err error
+
+	handler := HTTPMiddleware(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		// Authentication verifies API keys starting with "valid"
+		if apiKey := r.Header.Get(APIKeyHeader); strings.HasPrefix(apiKey, "valid") {
+			ctx = ContextWithAPIKey(ctx, apiKey)
+		}
+
+		_, err = e(ctx, nil)
+	}))
+
+	do := func(apiKey string) http.Header {
+		req := httptest.NewRequest(http.MethodPost, "/todos", nil)
+		req.Header.Set(APIKeyHeader, apiKey)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		return rec.Header()
+	}
+
+	header := do("valid-key")
+	require.NoError(t, err)
+
+	assert.Equal(t, "1", header.Get(LimitHeader))
+	assert.Equal(t, "0", header.Get(RemainingHeader))
+	assert.Equal(t, "1", header.Get(ResetHeader))
+	assert.Empty(t, header.Get(RetryAfterHeader))
+
+	header = do("valid-key")
+	require.Error(t, err)
+
+	assert.True(t, IsLimitExceededError(err))
+	assert.Equal(t, "1", header.Get(RetryAfterHeader))
+
+	// Clients are identified by their verified API key
+	do("valid-other")
+	require.NoError(t, err)
+
+	// Clients with unverified API keys are identified by their IP address (sending new keys does not help)
+	do("random-1")
+	require.NoError(t, err)
+
+	do("random-2")
+	require.Error(t, err)
+	assert.True(t, IsLimitExceededError(err))
+}
+
+func TestMiddleware_HTTP_TrustedProxies(t *testing.T) {
+	limiter := NewLimiter(Config{Enabled: true, Key: KeyIP, Limit: 1, Burst: 1, TrustedProxies: []string{"10.0.0.0/8"}})
+	e := newTestEndpoint(limiter)
+
+	var err error
+
+	handler := HTTPMiddleware(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err = e(r.Context(), nil)
+	}))
+
+	do := func(remoteAddr string, forwardedFor string) error {
+		req := httptest.NewRequest(http.MethodPost, "/todos", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(ForwardedForHeader, forwardedFor)
+
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		return err
+	}
+
+	require.NoError(t, do("10.0.0.1:1234", "192.0.2.1"))
+
+	// Clients behind proxies have buckets of their own
+	require.NoError(t, do("10.0.0.2:1234", "192.0.2.2"))
+	require.Error(t, do("10.0.0.2:1234", "192.0.2.1"))
+
+	// The header is ignored when the request does not come from a trusted proxy
+	require.NoError(t, do("192.0.2.3:1234", "192.0.2.4"))
+	require.Error(t, do("192.0.2.3:1234", "192.0.2.5"))
+}
+
+func TestMiddleware_GRPC(t *testing.T) {
+	limiter := NewLimiter(Config{Enabled: true, Key: KeyIP, Limit: 1, Burst: 1, TrustedProxies: []string{"10.0.0.1"}})
+	e := newTestEndpoint(limiter)
+
+	call := func(ip string, md metadata.MD) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234}})
+		ctx = GRPCToContext(limiter)(ctx, md)
+
+		_, err := e(ctx, nil)
+
+		return err
+	}
+
+	require.NoError(t, call("127.0.0.1", metadata.MD{}))
+
+	err := call("127.0.0.1", metadata.MD{})
+	require.Error(t, err)
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	require.NoError(t, call("127.0.0.2", metadata.Pairs(ForwardedForHeader, "127.0.0.1")))
+
+	// Clients behind a trusted proxy are identified by the forwarded address
+	require.Error(t, call("10.0.0.1", metadata.Pairs(ForwardedForHeader, "127.0.0.1")))
+}
+
+func TestMiddleware_Disabled(t *testing.T) {
+	e := newTestEndpoint(NewLimiter(Config{Enabled: false}))
+
+	for i := 0; i < 10; i++ {
+		_, err := e(context.Background(), nil)
+		require.NoError(t, err)
+	}
+}
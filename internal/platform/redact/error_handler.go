@@ -0,0 +1,88 @@
+package redact
+
+import (
+	"context"
+
+	"emperror.dev/emperror"
+	"emperror.dev/errors"
+)
+
+// ErrorHandler returns an error handler that masks sensitive information in errors
+// (both in the error message and in the error details) before passing them to the underlying handler.
+//
+// It should be the innermost handler in the chain: the redacted error is opaque,
+// so other handlers (eg. filters) cannot inspect the original error anymore.
+func ErrorHandler(handler emperror.ErrorHandlerFacade, redactor *Redactor) emperror.ErrorHandlerFacade {
+	return errorHandler{
+		handler:  handler,
+		redactor: redactor,
+	}
+}
+
+type errorHandler struct {
+	handler  emperror.ErrorHandlerFacade
+	redactor *Redactor
+}
+
+func (h errorHandler) Handle(err error) {
+	if err == nil {
+		return
+	}
+
+	h.handler.Handle(h.redactor.RedactError(err))
+}
+
+func (h errorHandler) HandleContext(ctx context.Context, err error) {
+	if err == nil {
+		return
+	}
+
+	h.handler.HandleContext(ctx, h.redactor.RedactError(err))
+}
+
+// RedactError returns an error with sensitive information masked in the error message and details.
+//
+// The stack trace (if any) is preserved, but the original error chain is not accessible from the returned error.
+func (r *Redactor) RedactError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	redacted := &redactedError{
+		msg:     r.Redact(err.Error()),
+		details: r.RedactDetails(errors.GetDetails(err)),
+	}
+
+	var stackTracer interface{ StackTrace() errors.StackTrace }
+	if errors.As(err, &stackTracer) {
+		redacted.stackTrace = stackTracer.StackTrace()
+	}
+
+	if errs := errors.GetErrors(err); len(errs) > 1 {
+		for _, e := range errs {
+			redacted.errs = append(redacted.errs, r.RedactError(e))
+		}
+	}
+
+	return redacted
+}
+
+type redactedError struct {
+	msg        string
+	details    []interface{}
+	stackTrace errors.StackTrace
+	errs       []error
+}
+
+func (e *redactedError) Error() string                 { return e.msg }
+func (e *redactedError) Details() []interface{}        { return e.details }
+func (e *redactedError) StackTrace() errors.StackTrace { return e.stackTrace }
+
+// Errors returns the redacted list of errors if the original error was a multi error.
+func (e *redactedError) Errors() []error {
+	if len(e.errs) == 0 {
+		return []error{e}
+	}
+
+	return e.errs
+}
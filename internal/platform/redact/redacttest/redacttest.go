@@ -0,0 +1,35 @@
+// Package redacttest provides test helpers for asserting that no sensitive information leaks.
+package redacttest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"logur.dev/logur"
+)
+
+// AssertNoSecrets fails the test if any of the secrets appear in the output.
+func AssertNoSecrets(t testing.TB, output string, secrets ...string) {
+	t.Helper()
+
+	for _, secret := range secrets {
+		if secret != "" && strings.Contains(output, secret) {
+			t.Errorf("secret %q leaked in output: %s", secret, output)
+		}
+	}
+}
+
+// AssertNoSecretsInEvents fails the test if any of the secrets appear in the log events
+// (either in the message or in any of the fields).
+func AssertNoSecretsInEvents(t testing.TB, events []logur.LogEvent, secrets ...string) {
+	t.Helper()
+
+	for _, event := range events {
+		AssertNoSecrets(t, event.Line, secrets...)
+
+		for key, value := range event.Fields {
+			AssertNoSecrets(t, fmt.Sprintf("%s=%+v", key, value), secrets...)
+		}
+	}
+}
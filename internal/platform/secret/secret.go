@@ -0,0 +1,76 @@
+// Package secret loads secrets referenced from configuration (Docker/Kubernetes secrets style).
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+
+	"emperror.dev/errors"
+)
+
+const (
+	// FilePrefix marks a value read from a file (eg. file:///run/secrets/db-password).
+	FilePrefix = "file://"
+
+	// EnvPrefix marks a value read from an environment variable (eg. env://DB_PASSWORD).
+	EnvPrefix = "env://"
+)
+
+// IsReference checks if a value is a secret reference.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, FilePrefix) || strings.HasPrefix(value, EnvPrefix)
+}
+
+// Resolve returns the value a secret reference points to.
+// Values that are not references are returned as is.
+func Resolve(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, FilePrefix):
+		return ReadFile(strings.TrimPrefix(value, FilePrefix))
+
+	case strings.HasPrefix(value, EnvPrefix):
+		name := strings.TrimPrefix(value, EnvPrefix)
+
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return "", errors.NewWithDetails("referenced environment variable is not set", "name", name)
+		}
+
+		return v, nil
+	}
+
+	return value, nil
+}
+
+// ReadFile reads a secret from a file.
+//
+// The file must be a regular file that is not writable by group or others.
+// Trailing newlines are removed from the content.
+func ReadFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", errors.WrapIfWithDetails(err, "failed to read secret file", "path", path)
+	}
+
+	if !info.Mode().IsRegular() {
+		return "", errors.NewWithDetails("secret file is not a regular file", "path", path)
+	}
+
+	// Windows does not support Unix permissions
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0o022 != 0 {
+		return "", errors.NewWithDetails(
+			"secret file must not be writable by group or others",
+			"path", path,
+			"mode", info.Mode().Perm().String(),
+		)
+	}
+
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.WrapIfWithDetails(err, "failed to read secret file", "path", path)
+	}
+
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
@@ -0,0 +1,60 @@
+package secret
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "password")
+	require.NoError(t, ioutil.WriteFile(path, []byte("secret\n"), 0400))
+
+	t.Setenv("SECRET_TEST_PASSWORD", "env-secret")
+
+	tests := map[string]string{
+		"plain":                         "plain",
+		"file://" + path:                "secret",
+		"env://SECRET_TEST_PASSWORD":    "env-secret",
+		"https://user@example.com/path": "https://user@example.com/path",
+	}
+
+	for value, expected := range tests {
+		actual, err := Resolve(value)
+		require.NoError(t, err, value)
+
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestResolve_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	insecurePath := filepath.Join(dir, "insecure")
+	require.NoError(t, ioutil.WriteFile(insecurePath, []byte("secret"), 0600))
+	require.NoError(t, os.Chmod(insecurePath, 0666))
+
+	tests := map[string]string{
+		"file://" + filepath.Join(dir, "missing"): "failed to read secret file",
+		"file://" + dir:             "secret file is not a regular file",
+		"env://SECRET_TEST_MISSING": "referenced environment variable is not set",
+	}
+
+	if runtime.GOOS != "windows" {
+		tests["file://"+insecurePath] = "secret file must not be writable by group or others"
+	}
+
+	for value, expected := range tests {
+		_, err := Resolve(value)
+		require.Error(t, err, value)
+
+		assert.Contains(t, err.Error(), expected)
+	}
+}
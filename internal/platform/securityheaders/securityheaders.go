@@ -0,0 +1,128 @@
+// Package securityheaders provides an HTTP middleware setting security related response headers
+// that can be reconfigured at runtime.
+package securityheaders
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+)
+
+// Config configures security headers.
+type Config struct {
+	// HSTS configures HTTP Strict Transport Security.
+	HSTS HSTSConfig
+
+	// ContentTypeNosniff prevents browsers from guessing the content type of responses (X-Content-Type-Options).
+	ContentTypeNosniff bool
+
+	// ReferrerPolicy controls the referrer information sent by browsers (Referrer-Policy). Empty disables the header.
+	ReferrerPolicy string
+
+	// ContentSecurityPolicy is the content security policy of HTML pages (eg. the landing page).
+	// Empty disables the header.
+	ContentSecurityPolicy string
+}
+
+// HSTSConfig configures HTTP Strict Transport Security.
+//
+// Browsers ignore the header received over plain HTTP,
+// so it only takes effect when the server is accessed over TLS (directly or through a proxy).
+type HSTSConfig struct {
+	// Enabled enables sending the Strict-Transport-Security header.
+	Enabled bool
+
+	// MaxAge is the time browsers should only access the server over TLS.
+	MaxAge time.Duration
+
+	// IncludeSubdomains applies the policy to subdomains as well.
+	IncludeSubdomains bool
+
+	// Preload allows the domain to be included in browser preload lists.
+	Preload bool
+}
+
+// Validate validates the configuration.
+func (c Config) Validate() error {
+	if c.HSTS.Enabled {
+		if c.HSTS.MaxAge < 0 {
+			return errors.New("hsts max age must not be negative")
+		}
+
+		if c.HSTS.Preload && !c.HSTS.IncludeSubdomains {
+			return errors.New("hsts preload requires including subdomains")
+		}
+	}
+
+	return nil
+}
+
+// Handler sets security headers on responses. Its configuration can be changed at runtime.
+type Handler struct {
+	headers atomic.Value // http.Header
+	csp     atomic.Value // string
+}
+
+// NewHandler returns a new Handler.
+func NewHandler(config Config) *Handler {
+	h := &Handler{}
+
+	h.SetConfig(config)
+
+	return h
+}
+
+// SetConfig changes the security header configuration.
+func (h *Handler) SetConfig(config Config) {
+	headers := make(http.Header)
+
+	if config.HSTS.Enabled {
+		value := "max-age=" + strconv.FormatInt(int64(config.HSTS.MaxAge.Seconds()), 10)
+
+		if config.HSTS.IncludeSubdomains {
+			value += "; includeSubDomains"
+		}
+
+		if config.HSTS.Preload {
+			value += "; preload"
+		}
+
+		headers.Set("Strict-Transport-Security", value)
+	}
+
+	if config.ContentTypeNosniff {
+		headers.Set("X-Content-Type-Options", "nosniff")
+	}
+
+	if config.ReferrerPolicy != "" {
+		headers.Set("Referrer-Policy", config.ReferrerPolicy)
+	}
+
+	h.headers.Store(headers)
+	h.csp.Store(config.ContentSecurityPolicy)
+}
+
+// Middleware sets security headers on every response.
+func (h *Handler) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for key, values := range h.headers.Load().(http.Header) {
+			w.Header().Set(key, values[0])
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ContentSecurityPolicy sets the content security policy on responses of an HTML page.
+func (h *Handler) ContentSecurityPolicy(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if csp := h.csp.Load().(string); csp != "" {
+			w.Header().Set("Content-Security-Policy", csp)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
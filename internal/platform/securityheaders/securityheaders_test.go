@@ -0,0 +1,131 @@
+package securityheaders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// okHandler does not set any headers (unlike http.NotFoundHandler).
+var okHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}) // nolint: gochecknoglobals
+
+func serve(handler http.Handler) http.Header {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	return rec.Header()
+}
+
+func TestHandler_HSTS(t *testing.T) {
+	tests := map[string]struct {
+		config   HSTSConfig
+		expected string
+	}{
+		"disabled": {
+			config:   HSTSConfig{MaxAge: time.Hour},
+			expected: "",
+		},
+		"maxAge": {
+			config:   HSTSConfig{Enabled: true, MaxAge: time.Hour},
+			expected: "max-age=3600",
+		},
+		"includeSubdomains": {
+			config:   HSTSConfig{Enabled: true, MaxAge: time.Hour, IncludeSubdomains: true},
+			expected: "max-age=3600; includeSubDomains",
+		},
+		"preload": {
+			config:   HSTSConfig{Enabled: true, MaxAge: time.Hour, IncludeSubdomains: true, Preload: true},
+			expected: "max-age=3600; includeSubDomains; preload",
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			handler := NewHandler(Config{HSTS: test.config})
+
+			header := serve(handler.Middleware(okHandler))
+
+			assert.Equal(t, test.expected, header.Get("Strict-Transport-Security"))
+		})
+	}
+}
+
+func TestHandler_ContentTypeNosniff(t *testing.T) {
+	handler := NewHandler(Config{ContentTypeNosniff: true})
+
+	assert.Equal(t, "nosniff", serve(handler.Middleware(okHandler)).Get("X-Content-Type-Options"))
+
+	handler.SetConfig(Config{})
+
+	assert.Empty(t, serve(handler.Middleware(okHandler)).Get("X-Content-Type-Options"))
+}
+
+func TestHandler_ReferrerPolicy(t *testing.T) {
+	handler := NewHandler(Config{ReferrerPolicy: "no-referrer"})
+
+	assert.Equal(t, "no-referrer", serve(handler.Middleware(okHandler)).Get("Referrer-Policy"))
+
+	handler.SetConfig(Config{})
+
+	assert.Empty(t, serve(handler.Middleware(okHandler)).Get("Referrer-Policy"))
+}
+
+func TestHandler_ContentSecurityPolicy(t *testing.T) {
+	handler := NewHandler(Config{ContentSecurityPolicy: "default-src 'self'"})
+
+	assert.Equal(
+		t,
+		"default-src 'self'",
+		serve(handler.ContentSecurityPolicy(okHandler)).Get("Content-Security-Policy"),
+	)
+
+	// The policy is only set on pages it is explicitly applied to
+	assert.Empty(t, serve(handler.Middleware(okHandler)).Get("Content-Security-Policy"))
+
+	handler.SetConfig(Config{})
+
+	assert.Empty(t, serve(handler.ContentSecurityPolicy(okHandler)).Get("Content-Security-Policy"))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]struct {
+		config Config
+		valid  bool
+	}{
+		"default": {
+			config: Config{},
+			valid:  true,
+		},
+		"hsts": {
+			config: Config{HSTS: HSTSConfig{Enabled: true, MaxAge: time.Hour, IncludeSubdomains: true, Preload: true}},
+			valid:  true,
+		},
+		"hstsNegativeMaxAge": {
+			config: Config{HSTS: HSTSConfig{Enabled: true, MaxAge: -time.Hour}},
+			valid:  false,
+		},
+		"hstsPreloadWithoutSubdomains": {
+			config: Config{HSTS: HSTSConfig{Enabled: true, MaxAge: time.Hour, Preload: true}},
+			valid:  false,
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.config.Validate()
+
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"logur.dev/logur"
+)
+
+// Component is an application component that can be shut down gracefully.
+type Component struct {
+	// Name identifies the component in logs.
+	Name string
+
+	// Drain stops accepting new work and waits for in-flight work to finish or the context to be canceled.
+	Drain func(ctx context.Context) error
+
+	// Close forcefully closes the component, cutting off any in-flight work.
+	Close func() error
+
+	// InFlight optionally reports the amount of unfinished work.
+	// It is logged when the component is closed forcefully.
+	InFlight func() int
+}
+
+// Coordinator shuts down application components gracefully.
+//
+// Shutting down happens in the following order:
+//   - pre-stop hooks are called (eg. to fail the readiness probe)
+//   - the coordinator waits for the pre-stop delay, so that load balancers can stop sending traffic
+//   - components are drained concurrently within the shutdown timeout
+//   - components that did not finish draining in time are closed forcefully
+type Coordinator struct {
+	preStopDelay time.Duration
+	timeout      time.Duration
+	logger       logur.Logger
+
+	preStop    []func()
+	components []Component
+
+	once sync.Once
+}
+
+// NewCoordinator returns a new Coordinator.
+func NewCoordinator(preStopDelay time.Duration, timeout time.Duration, logger logur.Logger) *Coordinator {
+	return &Coordinator{
+		preStopDelay: preStopDelay,
+		timeout:      timeout,
+		logger:       logger,
+	}
+}
+
+// OnPreStop registers a function called before draining components.
+func (c *Coordinator) OnPreStop(fn func()) {
+	c.preStop = append(c.preStop, fn)
+}
+
+// Register registers a component to be drained during shutdown.
+func (c *Coordinator) Register(component Component) {
+	c.components = append(c.components, component)
+}
+
+// Interrupt shuts down registered components.
+// It can be used as an interrupt function in a run group.
+func (c *Coordinator) Interrupt(_ error) {
+	c.Shutdown()
+}
+
+// Shutdown shuts down registered components.
+// Subsequent calls block until the first shutdown finishes.
+func (c *Coordinator) Shutdown() {
+	c.once.Do(c.shutdown)
+}
+
+func (c *Coordinator) shutdown() {
+	c.logger.Info("shutting down", map[string]interface{}{
+		"pre_stop_delay": c.preStopDelay.String(),
+		"timeout":        c.timeout.String(),
+	})
+
+	for _, fn := range c.preStop {
+		fn()
+	}
+
+	if c.preStopDelay > 0 {
+		time.Sleep(c.preStopDelay)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+
+	for _, component := range c.components {
+		wg.Add(1)
+
+		go func(component Component) {
+			defer wg.Done()
+
+			c.drain(ctx, component)
+		}(component)
+	}
+
+	wg.Wait()
+
+	c.logger.Info("shutdown complete")
+}
+
+func (c *Coordinator) drain(ctx context.Context, component Component) {
+	logger := logur.WithField(c.logger, "component", component.Name)
+
+	start := time.Now()
+
+	err := component.Drain(ctx)
+	if err == nil {
+		logger.Debug("component drained", map[string]interface{}{"duration": time.Since(start).String()})
+
+		return
+	}
+
+	fields := map[string]interface{}{"error": err.Error()}
+
+	if component.InFlight != nil {
+		fields["in_flight"] = component.InFlight()
+	}
+
+	logger.Warn("component did not drain in time, closing forcefully", fields)
+
+	if component.Close == nil {
+		return
+	}
+
+	if err := component.Close(); err != nil {
+		logger.Error("failed to close component", map[string]interface{}{"error": err.Error()})
+	}
+}
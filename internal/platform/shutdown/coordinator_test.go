@@ -0,0 +1,110 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"logur.dev/logur"
+)
+
+func TestCoordinator(t *testing.T) {
+	logger := &logur.TestLoggerFacade{}
+
+	coordinator := NewCoordinator(10*time.Millisecond, 50*time.Millisecond, logger)
+
+	var preStopAt time.Time
+
+	coordinator.OnPreStop(func() { preStopAt = time.Now() })
+
+	var drainedAt time.Time
+
+	coordinator.Register(Component{
+		Name: "fast",
+		Drain: func(ctx context.Context) error {
+			drainedAt = time.Now()
+
+			return nil
+		},
+		Close: func() error {
+			t.Error("drained component should not be closed")
+
+			return nil
+		},
+	})
+
+	var closed int32
+
+	coordinator.Register(Component{
+		Name: "slow",
+		Drain: func(ctx context.Context) error {
+			<-ctx.Done()
+
+			return ctx.Err()
+		},
+		Close: func() error {
+			atomic.StoreInt32(&closed, 1)
+
+			return nil
+		},
+		InFlight: func() int { return 3 },
+	})
+
+	coordinator.Shutdown()
+
+	// Subsequent calls are no-ops
+	coordinator.Interrupt(nil)
+
+	require.False(t, preStopAt.IsZero())
+	assert.GreaterOrEqual(t, int64(drainedAt.Sub(preStopAt)), int64(10*time.Millisecond), "pre-stop delay should be respected")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&closed))
+
+	var warnings []logur.LogEvent
+
+	for _, event := range logger.Events() {
+		if event.Level == logur.Warn {
+			warnings = append(warnings, event)
+		}
+	}
+
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "slow", warnings[0].Fields["component"])
+	assert.Equal(t, 3, warnings[0].Fields["in_flight"])
+}
+
+func TestHTTPServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(started)
+			<-release
+		}),
+	}
+
+	component := HTTPServer("http", server)
+
+	go func() { _ = server.Serve(ln) }()
+	go func() { _, _ = http.Get("http://" + ln.Addr().String()) }() // nolint: bodyclose,noctx
+
+	<-started
+
+	assert.Equal(t, 1, component.InFlight())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.Error(t, component.Drain(ctx))
+	assert.NoError(t, component.Close())
+
+	close(release)
+}
@@ -0,0 +1,98 @@
+package shutdown
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc"
+)
+
+// HTTPServer returns a component draining an HTTP server.
+//
+// It tracks active connections of the server, so it must be called before the server starts serving.
+func HTTPServer(name string, server *http.Server) Component {
+	tracker := &connTracker{
+		conns: make(map[net.Conn]http.ConnState),
+	}
+
+	connState := server.ConnState
+	server.ConnState = func(conn net.Conn, state http.ConnState) {
+		tracker.track(conn, state)
+
+		if connState != nil {
+			connState(conn, state)
+		}
+	}
+
+	return Component{
+		Name:     name,
+		Drain:    server.Shutdown,
+		Close:    server.Close,
+		InFlight: tracker.active,
+	}
+}
+
+// connTracker keeps track of HTTP connections.
+type connTracker struct {
+	conns map[net.Conn]http.ConnState
+	mu    sync.Mutex
+}
+
+func (t *connTracker) track(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	switch state {
+	case http.StateClosed, http.StateHijacked:
+		delete(t.conns, conn)
+
+	default:
+		t.conns[conn] = state
+	}
+}
+
+// active returns the number of connections with requests in progress.
+func (t *connTracker) active() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var active int
+
+	for _, state := range t.conns {
+		if state == http.StateActive {
+			active++
+		}
+	}
+
+	return active
+}
+
+// GRPCServer returns a component draining a gRPC server.
+func GRPCServer(name string, server *grpc.Server) Component {
+	return Component{
+		Name: name,
+		Drain: func(ctx context.Context) error {
+			done := make(chan struct{})
+
+			go func() {
+				server.GracefulStop()
+				close(done)
+			}()
+
+			select {
+			case <-done:
+				return nil
+
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+		Close: func() error {
+			server.Stop()
+
+			return nil
+		},
+	}
+}
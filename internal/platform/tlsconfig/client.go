@@ -0,0 +1,37 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+
+	"emperror.dev/errors"
+)
+
+// NewClientConfig returns a TLS configuration for clients.
+//
+// The server certificate is verified using the certificate authorities in caFile (system roots if empty).
+// The client certificate (certFile and keyFile) is presented to servers requiring mutual TLS.
+func NewClientConfig(caFile string, certFile string, keyFile string) (*tls.Config, error) {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+
+		config.RootCAs = pool
+	}
+
+	if certFile != "" || keyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.WrapIf(err, "failed to load tls client certificate")
+		}
+
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
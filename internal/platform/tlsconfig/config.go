@@ -0,0 +1,98 @@
+// Package tlsconfig configures TLS for servers and clients.
+package tlsconfig
+
+import (
+	"crypto/tls"
+
+	"emperror.dev/errors"
+)
+
+// Config holds details necessary for serving TLS.
+type Config struct {
+	// Enabled enables TLS.
+	Enabled bool
+
+	// CertFile and KeyFile hold the server certificate and its private key (PEM encoded).
+	// Certificates are reloaded when the files change.
+	CertFile string
+	KeyFile  string
+
+	// ClientCAFile holds the certificate authorities used to verify client certificates (PEM encoded).
+	// Setting it enables mutual TLS: clients are required to present a valid certificate.
+	ClientCAFile string
+
+	// MinVersion is the minimum accepted TLS version (1.0, 1.1, 1.2 or 1.3). Defaults to 1.2.
+	MinVersion string
+
+	// CipherSuites is the list of enabled cipher suites for TLS 1.2 and earlier (eg. TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256).
+	// Defaults to the Go default list.
+	CipherSuites []string
+}
+
+// Validate validates the configuration.
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.CertFile == "" {
+		return errors.New("tls certificate file is required")
+	}
+
+	if c.KeyFile == "" {
+		return errors.New("tls key file is required")
+	}
+
+	if _, err := minVersion(c.MinVersion); err != nil {
+		return err
+	}
+
+	if _, err := cipherSuites(c.CipherSuites); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func minVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+
+	case "1.0":
+		return tls.VersionTLS10, nil
+
+	case "1.1":
+		return tls.VersionTLS11, nil
+
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+
+	return 0, errors.New("tls min version must be 1.0, 1.1, 1.2 or 1.3")
+}
+
+func cipherSuites(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	suites := make(map[string]uint16)
+
+	for _, suite := range tls.CipherSuites() {
+		suites[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+
+	for _, name := range names {
+		id, ok := suites[name]
+		if !ok {
+			return nil, errors.Errorf("unknown or insecure tls cipher suite %q", name)
+		}
+
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
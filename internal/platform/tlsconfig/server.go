@@ -0,0 +1,199 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"path/filepath"
+	"sync/atomic"
+
+	"emperror.dev/errors"
+	"github.com/fsnotify/fsnotify"
+	"logur.dev/logur"
+)
+
+// Server holds the TLS certificates of a server and reloads them when they change.
+type Server struct {
+	config Config
+	logger logur.Logger
+
+	minVersion   uint16
+	cipherSuites []uint16
+
+	certificate atomic.Value // *tls.Certificate
+	clientCAs   atomic.Value // *x509.CertPool
+}
+
+// NewServer returns a new Server with certificates loaded.
+func NewServer(config Config, logger logur.Logger) (*Server, error) {
+	minVersion, err := minVersion(config.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	cipherSuites, err := cipherSuites(config.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		config:       config,
+		logger:       logger,
+		minVersion:   minVersion,
+		cipherSuites: cipherSuites,
+	}
+
+	if err := s.Reload(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Reload loads certificates from the configured files.
+// Previously loaded certificates are kept if loading fails.
+func (s *Server) Reload() error {
+	certificate, err := tls.LoadX509KeyPair(s.config.CertFile, s.config.KeyFile)
+	if err != nil {
+		return errors.WrapIf(err, "failed to load tls certificate")
+	}
+
+	var clientCAs *x509.CertPool
+
+	if s.config.ClientCAFile != "" {
+		clientCAs, err = loadCertPool(s.config.ClientCAFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.certificate.Store(&certificate)
+	s.clientCAs.Store(clientCAs)
+
+	return nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	pem, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, errors.WrapIf(err, "failed to read certificate authority file")
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.NewWithDetails("no certificates found in certificate authority file", "file", file)
+	}
+
+	return pool, nil
+}
+
+// TLSConfig returns a TLS configuration using the current certificates on every handshake.
+func (s *Server) TLSConfig() *tls.Config {
+	config := &tls.Config{
+		MinVersion:   s.minVersion,
+		CipherSuites: s.cipherSuites,
+		NextProtos:   []string{"h2", "http/1.1"},
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return s.certificate.Load().(*tls.Certificate), nil
+		},
+	}
+
+	// Client certificates are verified manually, so that certificate authorities can be reloaded.
+	// Resumed sessions skip the verification, so they are disabled (a reloaded CA would not apply to them).
+	if s.config.ClientCAFile != "" {
+		config.ClientAuth = tls.RequireAnyClientCert
+		config.VerifyPeerCertificate = s.verifyClientCertificate
+		config.SessionTicketsDisabled = true
+	}
+
+	return config
+}
+
+func (s *Server) verifyClientCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+
+	for _, rawCert := range rawCerts {
+		cert, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return errors.WrapIf(err, "failed to parse client certificate")
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return errors.New("client certificate is required")
+	}
+
+	opts := x509.VerifyOptions{
+		Roots:         s.clientCAs.Load().(*x509.CertPool),
+		Intermediates: x509.NewCertPool(),
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	for _, cert := range certs[1:] {
+		opts.Intermediates.AddCert(cert)
+	}
+
+	_, err := certs[0].Verify(opts)
+
+	return errors.WrapIf(err, "failed to verify client certificate")
+}
+
+// Watch reloads certificates when the certificate files change until the context is canceled.
+//
+// Directories of the files are watched, so that atomic replacements (eg. Kubernetes secret updates) are detected.
+func (s *Server) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return errors.WrapIf(err, "failed to create file watcher")
+	}
+	defer watcher.Close()
+
+	dirs := make(map[string]bool)
+	files := make(map[string]bool)
+
+	for _, file := range []string{s.config.CertFile, s.config.KeyFile, s.config.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+
+		files[filepath.Clean(file)] = true
+
+		dir := filepath.Dir(file)
+		if dirs[dir] {
+			continue
+		}
+
+		if err := watcher.Add(dir); err != nil {
+			return errors.WrapIfWithDetails(err, "failed to watch certificate directory", "dir", dir)
+		}
+
+		dirs[dir] = true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event := <-watcher.Events:
+			// Kubernetes updates secret volumes by replacing the ..data symlink
+			if event.Op == fsnotify.Chmod || (!files[filepath.Clean(event.Name)] && filepath.Base(event.Name) != "..data") {
+				continue
+			}
+
+			if err := s.Reload(); err != nil {
+				s.logger.Error("failed to reload tls certificates", map[string]interface{}{"error": err.Error()})
+
+				continue
+			}
+
+			s.logger.Info("tls certificates reloaded")
+
+		case err := <-watcher.Errors:
+			s.logger.Error("failed to watch tls certificates", map[string]interface{}{"error": err.Error()})
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"logur.dev/logur"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue issues a certificate and writes it (and its key) to files in dir.
+func (ca *testCA) issue(t *testing.T, dir string, name string, serial int64, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	return certFile, keyFile
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newTestCA(t)
+
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, ioutil.WriteFile(caFile, ca.pem, 0600))
+
+	certFile, keyFile := ca.issue(t, dir, "server", 2, x509.ExtKeyUsageServerAuth)
+	clientCertFile, clientKeyFile := ca.issue(t, t.TempDir(), "client", 3, x509.ExtKeyUsageClientAuth)
+
+	server, err := NewServer(Config{
+		Enabled:      true,
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		ClientCAFile: caFile,
+	}, logur.NoopLogger{})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() { _ = server.Watch(ctx) }()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	httpServer := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})}
+	defer httpServer.Close()
+
+	go func() { _ = httpServer.Serve(tls.NewListener(ln, server.TLSConfig())) }()
+
+	serialNumber := func(caFile string, certFile string, keyFile string) (int64, error) {
+		config, err := NewClientConfig(caFile, certFile, keyFile)
+		require.NoError(t, err)
+
+		conn, err := tls.Dial("tcp", ln.Addr().String(), config)
+		if err != nil {
+			return 0, err
+		}
+		defer conn.Close()
+
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64(), nil
+	}
+
+	serial, err := serialNumber(caFile, clientCertFile, clientKeyFile)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), serial)
+
+	// Mutual TLS requires a client certificate
+	_, err = serialNumber(caFile, "", "")
+	if err == nil {
+		// TLS 1.3 reports client certificate errors after the handshake
+		config, _ := NewClientConfig(caFile, "", "")
+
+		conn, dialErr := tls.Dial("tcp", ln.Addr().String(), config)
+		require.NoError(t, dialErr)
+
+		_, err = conn.Read(make([]byte, 1))
+		_ = conn.Close()
+	}
+	assert.Error(t, err)
+
+	// Rotate the server certificate
+	ca.issue(t, dir, "server", 4, x509.ExtKeyUsageServerAuth)
+
+	assert.Eventually(t, func() bool {
+		serial, err := serialNumber(caFile, clientCertFile, clientKeyFile)
+
+		return err == nil && serial == 4
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestServer_NoSessionResumption(t *testing.T) {
+	dir := t.TempDir()
+
+	ca := newTestCA(t)
+
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, ioutil.WriteFile(caFile, ca.pem, 0600))
+
+	certFile, keyFile := ca.issue(t, dir, "server", 2, x509.ExtKeyUsageServerAuth)
+	clientCertFile, clientKeyFile := ca.issue(t, dir, "client", 3, x509.ExtKeyUsageClientAuth)
+
+	server, err := NewServer(Config{
+		Enabled:      true,
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		ClientCAFile: caFile,
+	}, logur.NoopLogger{})
+	require.NoError(t, err)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	httpServer := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})}
+	defer httpServer.Close()
+
+	go func() { _ = httpServer.Serve(tls.NewListener(ln, server.TLSConfig())) }()
+
+	clientConfig, err := NewClientConfig(caFile, clientCertFile, clientKeyFile)
+	require.NoError(t, err)
+
+	clientConfig.ClientSessionCache = tls.NewLRUClientSessionCache(1)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: clientConfig, DisableKeepAlives: true}}
+
+	// Client certificates are verified on every connection (against the current certificate authorities)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get("https://" + ln.Addr().String())
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.False(t, resp.TLS.DidResume)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := map[string]Config{
+		"tls certificate file is required": {
+			Enabled: true,
+		},
+		"tls key file is required": {
+			Enabled:  true,
+			CertFile: "server.crt",
+		},
+		"tls min version must be 1.0, 1.1, 1.2 or 1.3": {
+			Enabled:    true,
+			CertFile:   "server.crt",
+			KeyFile:    "server.key",
+			MinVersion: "1.4",
+		},
+		`unknown or insecure tls cipher suite "TLS_RSA_WITH_RC4_128_SHA"`: {
+			Enabled:      true,
+			CertFile:     "server.crt",
+			KeyFile:      "server.key",
+			CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"},
+		},
+	}
+
+	for name, test := range tests {
+		name, test := name, test
+
+		t.Run(name, func(t *testing.T) {
+			err := test.Validate()
+
+			assert.EqualError(t, err, name)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package watermill
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"emperror.dev/errors"
+	"github.com/AppsFlyer/go-sundheit/checks"
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+)
+
+// healthCheckTopic is the topic health check messages are published to.
+const healthCheckTopic = "healthcheck"
+
+// RouterCheck is a health check reporting whether a message router is running.
+//
+// The router should be run through the check, so that it can detect when the router stops.
+// The check fails until the router starts, so it should not be used as a liveness check (see LivenessCheck).
+type RouterCheck struct {
+	router  *message.Router
+	stopped uint32
+}
+
+// NewRouterCheck returns a new RouterCheck.
+func NewRouterCheck(router *message.Router) *RouterCheck {
+	return &RouterCheck{
+		router: router,
+	}
+}
+
+// Run runs the underlying router.
+func (c *RouterCheck) Run(ctx context.Context) error {
+	defer atomic.StoreUint32(&c.stopped, 1)
+
+	return c.router.Run(ctx)
+}
+
+// Name implements the checks.Check interface.
+func (c *RouterCheck) Name() string {
+	return "watermill.router"
+}
+
+// Execute implements the checks.Check interface.
+func (c *RouterCheck) Execute() (interface{}, error) {
+	if atomic.LoadUint32(&c.stopped) == 1 {
+		return nil, errors.New("message router stopped")
+	}
+
+	select {
+	case <-c.router.Running():
+		return "running", nil
+
+	default:
+		return nil, errors.New("message router is not running yet")
+	}
+}
+
+// LivenessCheck returns a health check that only fails once the router stopped.
+func (c *RouterCheck) LivenessCheck() checks.Check {
+	return &checks.CustomCheck{
+		CheckName: "watermill.router.live",
+		CheckFunc: func() (interface{}, error) {
+			if atomic.LoadUint32(&c.stopped) == 1 {
+				return nil, errors.New("message router stopped")
+			}
+
+			return "alive", nil
+		},
+	}
+}
+
+// NewPubSubCheck returns a health check that fails when a message cannot be sent
+// through the pub/sub (the message is published and received by the check) in time.
+func NewPubSubCheck(publisher message.Publisher, subscriber message.Subscriber, timeout time.Duration) checks.Check {
+	return &checks.CustomCheck{
+		CheckName: "watermill.pubsub",
+		CheckFunc: func() (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			// The subscription is closed when the context is canceled
+			messages, err := subscriber.Subscribe(ctx, healthCheckTopic)
+			if err != nil {
+				return nil, errors.WrapIf(err, "failed to subscribe to health check messages")
+			}
+
+			msg := message.NewMessage(watermill.NewUUID(), nil)
+
+			err = publisher.Publish(healthCheckTopic, msg)
+			if err != nil {
+				return nil, errors.WrapIf(err, "failed to publish health check message")
+			}
+
+			for {
+				select {
+				case received, ok := <-messages:
+					if !ok {
+						return nil, errors.New("health check subscription closed")
+					}
+
+					received.Ack()
+
+					if received.UUID == msg.UUID {
+						return nil, nil
+					}
+
+				case <-ctx.Done():
+					return nil, errors.New("health check message not received in time")
+				}
+			}
+		},
+	}
+}
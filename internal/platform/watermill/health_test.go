@@ -0,0 +1,71 @@
+package watermill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"logur.dev/logur"
+)
+
+func TestPubSubCheck(t *testing.T) {
+	logger := &logur.TestLoggerFacade{}
+
+	publisher, subscriber := NewPubSub(logger)
+	defer publisher.Close()
+
+	check := NewPubSubCheck(PublisherCorrelationID(publisher), SubscriberCorrelationID(subscriber), time.Second)
+
+	for i := 0; i < 3; i++ {
+		_, err := check.Execute()
+		require.NoError(t, err)
+	}
+
+	// Every health check message is received
+	for _, event := range logger.Events() {
+		assert.NotEqual(t, "No subscribers to send message", event.Line)
+	}
+}
+
+func TestPubSubCheck_Closed(t *testing.T) {
+	publisher, subscriber := NewPubSub(logur.NoopLogger{})
+	require.NoError(t, publisher.Close())
+
+	_, err := NewPubSubCheck(publisher, subscriber, time.Second).Execute()
+	assert.Error(t, err)
+}
+
+func TestRouterCheck(t *testing.T) {
+	router, err := NewRouter(logur.NoopLogger{})
+	require.NoError(t, err)
+
+	check := NewRouterCheck(router)
+	liveness := check.LivenessCheck()
+
+	_, err = check.Execute()
+	assert.EqualError(t, err, "message router is not running yet")
+
+	_, err = liveness.Execute()
+	assert.NoError(t, err, "liveness check should pass until the router stops")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error)
+	go func() { done <- check.Run(ctx) }()
+
+	<-router.Running()
+
+	_, err = check.Execute()
+	assert.NoError(t, err)
+
+	cancel()
+	require.NoError(t, <-done)
+
+	_, err = check.Execute()
+	assert.EqualError(t, err, "message router stopped")
+
+	_, err = liveness.Execute()
+	assert.EqualError(t, err, "message router stopped")
+}
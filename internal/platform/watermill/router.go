@@ -1,6 +1,8 @@
 package watermill
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	"emperror.dev/errors"
@@ -38,3 +40,113 @@ func NewRouter(logger logur.Logger) (*message.Router, error) {
 
 	return h, nil
 }
+
+// CloseRouter closes a router, waiting for handlers to stop or the context to be canceled.
+// It returns as soon as the context is canceled, the router keeps closing in the background.
+//
+// Messages being processed are not waited for (see InFlight).
+func CloseRouter(ctx context.Context, router *message.Router) error {
+	done := make(chan error, 1)
+
+	go func() {
+		done <- router.Close()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// InFlight counts messages being processed by router handlers.
+//
+// Closing a router does not wait for messages being processed, so InFlight should be used to wait for them.
+type InFlight struct {
+	handlers map[string]int
+	count    int
+	idle     chan struct{}
+
+	mu sync.Mutex
+}
+
+// Middleware counts messages while they are being handled.
+func (f *InFlight) Middleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		name := message.HandlerNameFromCtx(msg.Context())
+
+		f.add(name, 1)
+		defer f.add(name, -1)
+
+		return h(msg)
+	}
+}
+
+func (f *InFlight) add(name string, delta int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.handlers == nil {
+		f.handlers = make(map[string]int)
+	}
+
+	f.handlers[name] += delta
+
+	if f.handlers[name] == 0 {
+		delete(f.handlers, name)
+	}
+
+	f.count += delta
+
+	switch {
+	case f.count == 0 && f.idle != nil:
+		close(f.idle)
+		f.idle = nil
+
+	case f.count > 0 && f.idle == nil:
+		f.idle = make(chan struct{})
+	}
+}
+
+// Wait waits for messages being processed to finish or the context to be canceled.
+func (f *InFlight) Wait(ctx context.Context) error {
+	f.mu.Lock()
+	idle := f.idle
+	f.mu.Unlock()
+
+	if idle == nil {
+		return nil
+	}
+
+	select {
+	case <-idle:
+		return nil
+
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Count returns the number of messages being processed.
+func (f *InFlight) Count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.count
+}
+
+// Handlers returns the number of messages being processed by each handler.
+func (f *InFlight) Handlers() map[string]int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	handlers := make(map[string]int, len(f.handlers))
+
+	for name, n := range f.handlers {
+		handlers[name] = n
+	}
+
+	return handlers
+}
@@ -0,0 +1,91 @@
+package watermill
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"logur.dev/logur"
+)
+
+func TestInFlight(t *testing.T) {
+	publisher, subscriber := NewPubSub(logur.NoopLogger{})
+	defer publisher.Close()
+
+	router, err := NewRouter(logur.NoopLogger{})
+	require.NoError(t, err)
+
+	var inFlight InFlight
+	router.AddMiddleware(inFlight.Middleware)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	router.AddNoPublisherHandler("blocking", "topic", subscriber, func(_ *message.Message) error {
+		close(started)
+		<-release
+
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() { _ = router.Run(ctx) }()
+
+	<-router.Running()
+
+	require.NoError(t, publisher.Publish("topic", message.NewMessage(watermill.NewUUID(), nil)))
+
+	<-started
+
+	assert.Equal(t, 1, inFlight.Count())
+	assert.Equal(t, map[string]int{"blocking": 1}, inFlight.Handlers())
+
+	closeCtx, closeCancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer closeCancel()
+
+	require.NoError(t, CloseRouter(closeCtx, router))
+
+	start := time.Now()
+
+	assert.Equal(t, context.DeadlineExceeded, inFlight.Wait(closeCtx))
+	assert.Less(t, int64(time.Since(start)), int64(time.Second), "waiting should stop when the context is canceled")
+
+	release <- struct{}{}
+
+	require.NoError(t, inFlight.Wait(context.Background()))
+	assert.Equal(t, 0, inFlight.Count())
+	assert.Empty(t, inFlight.Handlers())
+}
+
+func TestCloseRouter_ContextCanceled(t *testing.T) {
+	router, err := NewRouter(logur.NoopLogger{})
+	require.NoError(t, err)
+
+	router.AddNoPublisherHandler("handler", "topic", blockingSubscriber{}, func(_ *message.Message) error { return nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	go func() { _ = router.Run(context.Background()) }()
+
+	<-router.Running()
+
+	assert.Equal(t, context.DeadlineExceeded, CloseRouter(ctx, router))
+}
+
+// blockingSubscriber is a subscriber whose subscriptions are never closed (so routers using it never stop).
+type blockingSubscriber struct{}
+
+func (blockingSubscriber) Subscribe(_ context.Context, _ string) (<-chan *message.Message, error) {
+	return make(chan *message.Message), nil
+}
+
+func (blockingSubscriber) Close() error {
+	return nil
+}
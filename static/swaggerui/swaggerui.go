@@ -0,0 +1,15 @@
+// Package swaggerui contains a Swagger UI (https://github.com/swagger-api/swagger-ui) distribution
+// configured to display the OpenAPI documents of the application.
+//
+// The distribution files are fetched by the swaggerui Makefile target (the version is in the VERSION file).
+package swaggerui
+
+import "embed"
+
+//go:embed index.html swagger-ui.css swagger-ui-bundle.js swagger-ui-standalone-preset.js
+var files embed.FS
+
+// Files returns a filesystem with the Swagger UI files.
+func Files() embed.FS {
+	return files
+}